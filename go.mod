@@ -1,3 +1,5 @@
 module github.com/webermarci/validator
 
 go 1.19
+
+require golang.org/x/text v0.21.0
@@ -0,0 +1,439 @@
+package validator
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"golang.org/x/text/language"
+)
+
+type ruleJSON struct {
+	Type     RuleType          `json:"type"`
+	Label    string            `json:"label,omitempty"`
+	Message  string            `json:"message,omitempty"`
+	Cost     int               `json:"cost,omitempty"`
+	Severity Severity          `json:"severity,omitempty"`
+	Disabled bool              `json:"disabled,omitempty"`
+	Scopes   [][2]int          `json:"scopes,omitempty"`
+	Language string            `json:"language,omitempty"`
+	Args     []json.RawMessage `json:"args,omitempty"`
+}
+
+type validatorJSON struct {
+	Rules                 []ruleJSON `json:"rules"`
+	DenyByDefault         bool       `json:"denyByDefault,omitempty"`
+	RequireAtLeastOneRule bool       `json:"requireAtLeastOneRule,omitempty"`
+	RedactInput           bool       `json:"redactInput,omitempty"`
+	FailFast              *bool      `json:"failFast,omitempty"`
+}
+
+type ruleBuilder func(v *Validator, args []json.RawMessage) error
+
+var ruleBuilders = map[RuleType]ruleBuilder{
+	NotEmpty:            noArgRule((*Validator).NotEmpty),
+	Required:            noArgRule((*Validator).Required),
+	StartsWith:          stringRule((*Validator).StartsWith),
+	EndsWith:            stringRule((*Validator).EndsWith),
+	StartsWithAny:       stringsRule((*Validator).StartsWithAny),
+	EndsWithAny:         stringsRule((*Validator).EndsWithAny),
+	StartsWithFold:      stringRule((*Validator).StartsWithFold),
+	EndsWithFold:        stringRule((*Validator).EndsWithFold),
+	NotStartsWith:       stringRule((*Validator).NotStartsWith),
+	NotEndsWith:         stringRule((*Validator).NotEndsWith),
+	LongerThan:          intRule((*Validator).LongerThan),
+	LongerThanOrEqual:   intRule((*Validator).LongerThanOrEqual),
+	ShorterThan:         intRule((*Validator).ShorterThan),
+	ShorterThanOrEqual:  intRule((*Validator).ShorterThanOrEqual),
+	MinLength:           intRule((*Validator).MinLength),
+	MaxLength:           intRule((*Validator).MaxLength),
+	LengthEqual:         intRule((*Validator).LengthEqual),
+	LengthDivisibleBy:   intRule((*Validator).LengthDivisibleBy),
+	LongerThanBytes:     intRule((*Validator).LongerThanBytes),
+	ShorterThanBytes:    intRule((*Validator).ShorterThanBytes),
+	Contains:            stringRule((*Validator).Contains),
+	ContainsFold:        stringRule((*Validator).ContainsFold),
+	ContainsWord:        stringRule((*Validator).ContainsWord),
+	ContainsAny:         stringsRule((*Validator).ContainsAny),
+	ContainsAll:         stringsRule((*Validator).ContainsAll),
+	ContainsAtLeast:     stringIntRule((*Validator).ContainsAtLeast),
+	ContainsExactly:     stringIntRule((*Validator).ContainsExactly),
+	NotContains:         stringRule((*Validator).NotContains),
+	NotContainsAny:      stringsRule((*Validator).NotContainsAny),
+	BlockList:           stringsRule((*Validator).BlockList),
+	BlockListFold:       stringsRule((*Validator).BlockListFold),
+	ContainsACharacter:  noArgRule((*Validator).ContainsACharacter),
+	ContainsANumber:     noArgRule((*Validator).ContainsANumber),
+	IsNumeric:           noArgRule((*Validator).IsNumeric),
+	NumericRange:        intPairRule((*Validator).NumericRange),
+	ValueDivisibleBy:    intRule((*Validator).ValueDivisibleBy),
+	IsAlphanumeric:      noArgRule((*Validator).IsAlphanumeric),
+	IsUppercase:         noArgRule((*Validator).IsUppercase),
+	IsLowercase:         noArgRule((*Validator).IsLowercase),
+	NoWhitespace:        noArgRule((*Validator).NoWhitespace),
+	Trimmed:             noArgRule((*Validator).Trimmed),
+	OnlyScript:          stringRule((*Validator).OnlyScript),
+	NoControlCharacters: noArgRule((*Validator).NoControlCharacters),
+	IsPrintableASCII:    noArgRule((*Validator).IsPrintableASCII),
+	RunesInRange:        runesInRangeRule,
+	EqualTo:             stringRule((*Validator).EqualTo),
+	NotEqualTo:          stringRule((*Validator).NotEqualTo),
+	LexBetween:          stringPairRule((*Validator).LexBetween),
+	LexBetweenExclusive: stringPairRule((*Validator).LexBetweenExclusive),
+	Ignore:              stringRule((*Validator).Ignore),
+	IgnoreFold:          stringRule((*Validator).IgnoreFold),
+	OneOf:               stringsRule((*Validator).OneOf),
+	OneOfFold:           stringsRule((*Validator).OneOfFold),
+	Regexp:              stringRule((*Validator).Regexp),
+	MatchesOneOf:        stringsRule((*Validator).MatchesOneOf),
+	Email:               noArgRule((*Validator).Email),
+	EmailStrict:         noArgRule((*Validator).EmailStrict),
+	EmailWithName:       noArgRule((*Validator).EmailWithName),
+	URL:                 variadicRule((*Validator).URL),
+	IsHostname:          noArgRule((*Validator).IsHostname),
+	IsFQDN:              noArgRule((*Validator).IsFQDN),
+	IsIP:                noArgRule((*Validator).IsIP),
+	IsIPv4:              noArgRule((*Validator).IsIPv4),
+	IsIPv6:              noArgRule((*Validator).IsIPv6),
+	IsMAC:               noArgRule((*Validator).IsMAC),
+	Date:                stringRule((*Validator).Date),
+	DateBetween:         dateBetweenRule,
+	IsDuration:          noArgRule((*Validator).IsDuration),
+	DurationBetween:     durationBetweenRule,
+	IsJSON:              noArgRule((*Validator).IsJSON),
+	IsHex:               noArgRule((*Validator).IsHex),
+	IsHexBytes:          noArgRule((*Validator).IsHexBytes),
+	IsBase64:            noArgRule((*Validator).IsBase64),
+	IsBase64URL:         noArgRule((*Validator).IsBase64URL),
+	IsUUID:              noArgRule((*Validator).IsUUID),
+	IsUUIDVersion:       intRule((*Validator).IsUUIDVersion),
+	IsCreditCard:        noArgRule((*Validator).IsCreditCard),
+	IsCreditCardBrand:   stringRule((*Validator).IsCreditCardBrand),
+	IsSlug:              noArgRule((*Validator).IsSlug),
+	IsSlugUnderscore:    noArgRule((*Validator).IsSlugUnderscore),
+	IsGoIdentifier:      noArgRule((*Validator).IsGoIdentifier),
+	IsPhone:             stringRule((*Validator).IsPhone),
+	IsSemVer:            noArgRule((*Validator).IsSemVer),
+	SemVerAtLeast:       stringRule((*Validator).SemVerAtLeast),
+	Not:                 notRule,
+	Any:                 anyRule,
+	Or:                  orRule,
+}
+
+func FromJSON(data []byte) (*Validator, error) {
+	v := NewValidator()
+	if err := json.Unmarshal(data, v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+func (v *Validator) MarshalJSON() ([]byte, error) {
+	rules := v.snapshot()
+	out := validatorJSON{
+		Rules:                 make([]ruleJSON, len(rules)),
+		DenyByDefault:         v.deniesByDefault(),
+		RequireAtLeastOneRule: v.requiresRules(),
+		RedactInput:           v.redacts(),
+	}
+	if !v.failsFast() {
+		out.FailFast = new(bool)
+	}
+	for i, r := range rules {
+		if _, found := ruleBuilders[r.ruleType]; !found {
+			return nil, fmt.Errorf("rule %s cannot be marshaled", r.ruleType)
+		}
+		out.Rules[i].Type = r.ruleType
+		out.Rules[i].Label = r.label
+		out.Rules[i].Message = r.message
+		out.Rules[i].Cost = r.cost
+		out.Rules[i].Severity = r.severity
+		out.Rules[i].Disabled = r.disabled
+		out.Rules[i].Scopes = r.scopes
+		out.Rules[i].Language = r.language
+		for _, arg := range r.args {
+			raw, err := json.Marshal(arg)
+			if err != nil {
+				return nil, err
+			}
+			out.Rules[i].Args = append(out.Rules[i].Args, raw)
+		}
+	}
+	return json.Marshal(out)
+}
+
+func (v *Validator) UnmarshalJSON(data []byte) error {
+	var in validatorJSON
+	if err := json.Unmarshal(data, &in); err != nil {
+		return err
+	}
+	if v.recents == nil {
+		v.recents = make(map[string]int64)
+	}
+	if v.close == nil {
+		v.close = make(chan struct{})
+	}
+	if v.now == nil {
+		v.now = time.Now
+	}
+	v.mutex.Lock()
+	v.rules = []*Rule{}
+	v.denyByDefault = in.DenyByDefault
+	v.requireRules = in.RequireAtLeastOneRule
+	v.redact = in.RedactInput
+	v.aggregate = in.FailFast != nil && !*in.FailFast
+	v.mutex.Unlock()
+	for _, r := range in.Rules {
+		build, found := ruleBuilders[r.Type]
+		if !found {
+			return fmt.Errorf("unknown rule %s", r.Type)
+		}
+		if err := buildInLanguage(v, build, r); err != nil {
+			return fmt.Errorf("rule %s: %w", r.Type, err)
+		}
+		if r.Label != "" {
+			v.Labeled(r.Label)
+		}
+		if r.Message != "" {
+			v.WithMessage(r.Message)
+		}
+		if r.Cost != 0 {
+			v.Cost(r.Cost)
+		}
+		switch r.Severity {
+		case "":
+		case SeverityError:
+			v.Error()
+		case SeverityWarning:
+			v.Warn()
+		default:
+			return fmt.Errorf("rule %s: unknown severity %s", r.Type, r.Severity)
+		}
+		for _, scope := range r.Scopes {
+			v.Substring(scope[0], scope[1])
+		}
+		if r.Disabled {
+			v.updateLast(func(r *Rule) {
+				r.disabled = true
+			})
+		}
+	}
+	return v.Err()
+}
+
+func (r Result) MarshalJSON() ([]byte, error) {
+	if r.Approval {
+		return []byte(`{"approved":true}`), nil
+	}
+	type result Result
+	return json.Marshal(result(r))
+}
+
+func buildInLanguage(v *Validator, build ruleBuilder, r ruleJSON) error {
+	if r.Language == "" {
+		return build(v, r.Args)
+	}
+	tag, err := language.Parse(r.Language)
+	if err != nil {
+		return err
+	}
+	v.mutex.RLock()
+	previous := v.language
+	v.mutex.RUnlock()
+	v.FoldLanguage(tag)
+	defer v.FoldLanguage(previous)
+	return build(v, r.Args)
+}
+
+func argCount(args []json.RawMessage, expected int) error {
+	if len(args) != expected {
+		return fmt.Errorf("expected %d arguments, got %d", expected, len(args))
+	}
+	return nil
+}
+
+func noArgRule(method func(*Validator) *Validator) ruleBuilder {
+	return func(v *Validator, args []json.RawMessage) error {
+		if err := argCount(args, 0); err != nil {
+			return err
+		}
+		method(v)
+		return nil
+	}
+}
+
+func stringRule(method func(*Validator, string) *Validator) ruleBuilder {
+	return func(v *Validator, args []json.RawMessage) error {
+		if err := argCount(args, 1); err != nil {
+			return err
+		}
+		var text string
+		if err := json.Unmarshal(args[0], &text); err != nil {
+			return err
+		}
+		method(v, text)
+		return nil
+	}
+}
+
+func stringsRule(method func(*Validator, []string) *Validator) ruleBuilder {
+	return func(v *Validator, args []json.RawMessage) error {
+		if err := argCount(args, 1); err != nil {
+			return err
+		}
+		var texts []string
+		if err := json.Unmarshal(args[0], &texts); err != nil {
+			return err
+		}
+		method(v, texts)
+		return nil
+	}
+}
+
+func variadicRule(method func(*Validator, ...string) *Validator) ruleBuilder {
+	return stringsRule(func(v *Validator, texts []string) *Validator {
+		return method(v, texts...)
+	})
+}
+
+func stringPairRule(method func(*Validator, string, string) *Validator) ruleBuilder {
+	return func(v *Validator, args []json.RawMessage) error {
+		if err := argCount(args, 2); err != nil {
+			return err
+		}
+		var a, b string
+		if err := json.Unmarshal(args[0], &a); err != nil {
+			return err
+		}
+		if err := json.Unmarshal(args[1], &b); err != nil {
+			return err
+		}
+		method(v, a, b)
+		return nil
+	}
+}
+
+func intRule(method func(*Validator, int) *Validator) ruleBuilder {
+	return func(v *Validator, args []json.RawMessage) error {
+		if err := argCount(args, 1); err != nil {
+			return err
+		}
+		var n int
+		if err := json.Unmarshal(args[0], &n); err != nil {
+			return err
+		}
+		method(v, n)
+		return nil
+	}
+}
+
+func stringIntRule(method func(*Validator, string, int) *Validator) ruleBuilder {
+	return func(v *Validator, args []json.RawMessage) error {
+		if err := argCount(args, 2); err != nil {
+			return err
+		}
+		var text string
+		if err := json.Unmarshal(args[0], &text); err != nil {
+			return err
+		}
+		var n int
+		if err := json.Unmarshal(args[1], &n); err != nil {
+			return err
+		}
+		method(v, text, n)
+		return nil
+	}
+}
+
+func intPairRule(method func(*Validator, int, int) *Validator) ruleBuilder {
+	return func(v *Validator, args []json.RawMessage) error {
+		if err := argCount(args, 2); err != nil {
+			return err
+		}
+		var a, b int
+		if err := json.Unmarshal(args[0], &a); err != nil {
+			return err
+		}
+		if err := json.Unmarshal(args[1], &b); err != nil {
+			return err
+		}
+		method(v, a, b)
+		return nil
+	}
+}
+
+func dateBetweenRule(v *Validator, args []json.RawMessage) error {
+	if err := argCount(args, 3); err != nil {
+		return err
+	}
+	var layout string
+	if err := json.Unmarshal(args[0], &layout); err != nil {
+		return err
+	}
+	var from, to time.Time
+	if err := json.Unmarshal(args[1], &from); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(args[2], &to); err != nil {
+		return err
+	}
+	v.DateBetween(layout, from, to)
+	return nil
+}
+
+func runesInRangeRule(v *Validator, args []json.RawMessage) error {
+	return intPairRule(func(v *Validator, low int, high int) *Validator {
+		return v.RunesInRange(rune(low), rune(high))
+	})(v, args)
+}
+
+func durationBetweenRule(v *Validator, args []json.RawMessage) error {
+	if err := argCount(args, 2); err != nil {
+		return err
+	}
+	var min, max time.Duration
+	if err := json.Unmarshal(args[0], &min); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(args[1], &max); err != nil {
+		return err
+	}
+	v.DurationBetween(min, max)
+	return nil
+}
+
+func notRule(v *Validator, args []json.RawMessage) error {
+	if err := argCount(args, 1); err != nil {
+		return err
+	}
+	inner := NewValidator()
+	if err := json.Unmarshal(args[0], inner); err != nil {
+		return err
+	}
+	v.not(inner)
+	return nil
+}
+
+func anyRule(v *Validator, args []json.RawMessage) error {
+	validators := make([]*Validator, len(args))
+	for i, arg := range args {
+		validators[i] = NewValidator()
+		if err := json.Unmarshal(arg, validators[i]); err != nil {
+			return err
+		}
+	}
+	v.Any(validators...)
+	return nil
+}
+
+func orRule(v *Validator, args []json.RawMessage) error {
+	validators := make([]*Validator, len(args))
+	for i, arg := range args {
+		validators[i] = NewValidator()
+		if err := json.Unmarshal(arg, validators[i]); err != nil {
+			return err
+		}
+	}
+	v.or(validators)
+	return nil
+}
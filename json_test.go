@@ -0,0 +1,191 @@
+package validator
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestJSONRoundTrip(t *testing.T) {
+	validator := NewValidator().
+		StartsWith("abc").
+		Labeled("prefix").
+		LongerThan(4).
+		WithMessage("{input} is too short").
+		OneOf([]string{"abcde", "abcdef", "abc12"}).
+		Regexp("^[a-z]+$").
+		Cost(1).
+		ContainsACharacter().
+		Not(func(v *Validator) *Validator {
+			return v.EndsWith("f")
+		}).
+		Any(
+			NewValidator().Contains("cd"),
+			NewValidator().Email(),
+		)
+
+	data, err := json.Marshal(validator)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := FromJSON(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if loaded.RuleCount() != validator.RuleCount() {
+		t.Fatal("invalid rule count", loaded.RuleCount(), validator.RuleCount())
+	}
+
+	for i, ruleType := range loaded.Rules() {
+		if ruleType != validator.Rules()[i] {
+			t.Fatal("invalid rule type", ruleType, validator.Rules()[i])
+		}
+	}
+
+	for _, input := range []string{"abcde", "abcdef", "abc12", "abc", "xyz"} {
+		expected := validator.Validate(input)
+		result := loaded.Validate(input)
+
+		if result.Approval != expected.Approval {
+			t.Fatal("invalid approval", input, result.Approval, expected.Approval)
+		}
+
+		if result.Reason != expected.Reason {
+			t.Fatal("invalid reason", result.Reason, expected.Reason)
+		}
+
+		if result.Label != expected.Label {
+			t.Fatal("invalid label", result.Label, expected.Label)
+		}
+	}
+
+	validator.SortRulesByCost()
+	loaded.SortRulesByCost()
+
+	for i, ruleType := range loaded.Rules() {
+		if ruleType != validator.Rules()[i] {
+			t.Fatal("invalid rule order by cost", loaded.Rules(), validator.Rules())
+		}
+	}
+}
+
+func TestJSONModes(t *testing.T) {
+	var tests = []struct {
+		name      string
+		validator *Validator
+	}{
+		{name: "DenyByDefault", validator: NewValidator().DenyByDefault()},
+		{name: "DenyByDefaultWithAny", validator: NewValidator().Any(NewValidator().EqualTo("y")).DenyByDefault()},
+		{name: "RequireAtLeastOneRule", validator: NewValidator().RequireAtLeastOneRule()},
+		{name: "FailFast", validator: NewValidator().FailFast(false).EqualTo("y").LongerThan(4)},
+		{name: "RedactInput", validator: NewValidator().RedactInput().EqualTo("y").WithMessage("{input} is invalid").LongerThan(4)},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			data, err := json.Marshal(test.validator)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			loaded, err := FromJSON(data)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for _, input := range []string{"x", "y"} {
+				result, expected := loaded.Validate(input), test.validator.Validate(input)
+				if result.Approval != expected.Approval || result.Reason != expected.Reason {
+					t.Fatal("mode lost in round trip", input, result, expected)
+				}
+			}
+		})
+	}
+}
+
+func TestJSONCustom(t *testing.T) {
+	validator := NewValidator().Custom("custom reason", func(input string) bool {
+		return true
+	})
+
+	if _, err := json.Marshal(validator); err == nil {
+		t.Fatal("error expected")
+	}
+}
+
+func TestJSONInvalid(t *testing.T) {
+	var tests = []struct {
+		name string
+		data string
+	}{
+		{name: "Syntax", data: `{"rules":`},
+		{name: "UnknownRule", data: `{"rules":[{"type":"unknown"}]}`},
+		{name: "MissingArgument", data: `{"rules":[{"type":"startsWith"}]}`},
+		{name: "InvalidArgument", data: `{"rules":[{"type":"longerThan","args":["4"]}]}`},
+		{name: "InvalidSeverity", data: `{"rules":[{"type":"notEmpty","severity":"fatal"}]}`},
+		{name: "InvalidRegexp", data: `{"rules":[{"type":"regexp","args":["[0-9]++"]}]}`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := FromJSON([]byte(test.data)); err == nil {
+				t.Fatal("error expected")
+			}
+		})
+	}
+}
+
+func TestResultJSON(t *testing.T) {
+	validator := NewValidator().StartsWith("a").Labeled("prefix").LongerThan(2).FailFast(false)
+
+	var tests = []struct {
+		name     string
+		result   *Result
+		expected string
+	}{
+		{
+			name:     "Approved",
+			result:   validator.Validate("abc"),
+			expected: `{"approved":true}`,
+		},
+		{
+			name:     "Denied",
+			result:   validator.Validate("b"),
+			expected: `{"approved":false,"ruleType":"startsWith","label":"prefix","reason":"\"starts with a\" is not met by \"b\"; \"longer than 2\" is not met by \"b\"","severity":"error","failures":["startsWith","longerThan"]}`,
+		},
+		{
+			name:     "Duplicate",
+			result:   &Result{RuleType: IgnoreDuplicates, Reason: "ignore duplication", Severity: SeverityError, RetryAfter: time.Second},
+			expected: `{"approved":false,"ruleType":"ignoreDuplicates","reason":"ignore duplication","severity":"error","retryAfter":1000000000}`,
+		},
+		{
+			name:     "ApprovedWithDetails",
+			result:   &Result{Approval: true, Reason: "ignored"},
+			expected: `{"approved":true}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			data, err := json.Marshal(test.result)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if string(data) != test.expected {
+				t.Fatal("invalid json", string(data))
+			}
+
+			var decoded Result
+			if err := json.Unmarshal(data, &decoded); err != nil {
+				t.Fatal(err)
+			}
+
+			if decoded.Approval != test.result.Approval {
+				t.Fatal("invalid approval", decoded.Approval)
+			}
+		})
+	}
+}
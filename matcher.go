@@ -0,0 +1,81 @@
+package validator
+
+type matcher struct {
+	next  []map[byte]int
+	fail  []int
+	match []int
+}
+
+func newMatcher(words []string) *matcher {
+	m := &matcher{
+		next:  []map[byte]int{{}},
+		fail:  []int{0},
+		match: []int{-1},
+	}
+	for i, word := range words {
+		if word == "" {
+			continue
+		}
+		state := 0
+		for j := 0; j < len(word); j++ {
+			next, found := m.next[state][word[j]]
+			if !found {
+				next = len(m.next)
+				m.next = append(m.next, map[byte]int{})
+				m.fail = append(m.fail, 0)
+				m.match = append(m.match, -1)
+				m.next[state][word[j]] = next
+			}
+			state = next
+		}
+		if m.match[state] < 0 {
+			m.match[state] = i
+		}
+	}
+	queue := []int{}
+	for _, next := range m.next[0] {
+		queue = append(queue, next)
+	}
+	for len(queue) > 0 {
+		state := queue[0]
+		queue = queue[1:]
+		for b, next := range m.next[state] {
+			fail := m.fail[state]
+			for {
+				if target, found := m.next[fail][b]; found {
+					m.fail[next] = target
+					break
+				}
+				if fail == 0 {
+					break
+				}
+				fail = m.fail[fail]
+			}
+			if m.match[next] < 0 {
+				m.match[next] = m.match[m.fail[next]]
+			}
+			queue = append(queue, next)
+		}
+	}
+	return m
+}
+
+func (m *matcher) find(input string) (int, bool) {
+	state := 0
+	for i := 0; i < len(input); i++ {
+		for {
+			if next, found := m.next[state][input[i]]; found {
+				state = next
+				break
+			}
+			if state == 0 {
+				break
+			}
+			state = m.fail[state]
+		}
+		if m.match[state] >= 0 {
+			return m.match[state], true
+		}
+	}
+	return -1, false
+}
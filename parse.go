@@ -0,0 +1,41 @@
+package validator
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Parse builds a validator from a spec like
+// `longerThan:4 & startsWith:abc & !contains:xyz`. Every term names a rule by
+// its RuleType, followed by its parameters after a colon, separated by spaces
+// as in struct tags. Terms are ANDed and a leading ! negates one.
+func Parse(spec string) (*Validator, error) {
+	v := NewValidator()
+	for _, term := range strings.Split(spec, "&") {
+		term = strings.TrimSpace(term)
+		if term == "" {
+			return nil, fmt.Errorf("empty term in %q", spec)
+		}
+		negated := strings.HasPrefix(term, "!")
+		term = strings.TrimSpace(strings.TrimPrefix(term, "!"))
+		name, param, _ := strings.Cut(term, ":")
+		build, found := ruleBuilders[RuleType(name)]
+		if !found {
+			return nil, fmt.Errorf("unknown rule %s", name)
+		}
+		target := v
+		if negated {
+			target = NewValidator()
+		}
+		if err := buildFromTag(target, build, strings.TrimSpace(param)); err != nil {
+			return nil, fmt.Errorf("rule %s: %w", name, err)
+		}
+		if err := target.Err(); err != nil {
+			return nil, err
+		}
+		if negated {
+			v.not(target)
+		}
+	}
+	return v, nil
+}
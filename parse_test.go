@@ -0,0 +1,87 @@
+package validator
+
+import (
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	var tests = []struct {
+		spec     string
+		approved []string
+		denied   []string
+	}{
+		{
+			spec:     "longerThan:4 & startsWith:abc & !contains:xyz",
+			approved: []string{"abcde", "abc12345"},
+			denied:   []string{"abcd", "xabcde", "abcxyz"},
+		},
+		{
+			spec:     "oneOf:red green blue",
+			approved: []string{"red", "blue"},
+			denied:   []string{"", "yellow"},
+		},
+		{
+			spec:     " notEmpty&!isNumeric ",
+			approved: []string{"abc", "12a"},
+			denied:   []string{"", "123"},
+		},
+		{
+			spec:     "numericRange:1 10 & email & !regexp:^[ab]",
+			approved: []string{},
+			denied:   []string{"5", "a@example.com"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.spec, func(t *testing.T) {
+			validator, err := Parse(test.spec)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for _, input := range test.approved {
+				if !validator.Validate(input).Approval {
+					t.Fatal("approve expected", input)
+				}
+			}
+
+			for _, input := range test.denied {
+				if validator.Validate(input).Approval {
+					t.Fatal("deny expected", input)
+				}
+			}
+		})
+	}
+
+	validator, err := Parse("startsWith:abc & !endsWith:f")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result := validator.Validate("abcdef")
+	if result.RuleType != Not || result.Reason != `"not ends with f" is not met by "abcdef"` {
+		t.Fatal("invalid result", result)
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	var tests = []struct {
+		name string
+		spec string
+	}{
+		{name: "Empty", spec: ""},
+		{name: "EmptyTerm", spec: "notEmpty & "},
+		{name: "UnknownRule", spec: "notEmpty & unknown"},
+		{name: "InvalidParameter", spec: "longerThan:abc"},
+		{name: "MissingParameter", spec: "startsWith"},
+		{name: "InvalidRegexp", spec: "!regexp:[0-9]++"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := Parse(test.spec); err == nil {
+				t.Fatal("error expected")
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package validator
+
+import (
+	"sync"
+	"time"
+)
+
+type pooledValidator struct {
+	validator      *Validator
+	ignoreDuration time.Duration
+}
+
+// ValidatorPool keeps configured validators for reuse. Validators in the pool
+// never run a duplicate cleanup goroutine: Put stops it and clears the
+// tracked inputs, and Get restarts it with the window the validator had.
+type ValidatorPool struct {
+	pool sync.Pool
+}
+
+func NewValidatorPool(build func() *Validator) *ValidatorPool {
+	return &ValidatorPool{
+		pool: sync.Pool{
+			New: func() interface{} {
+				return &pooledValidator{validator: build()}
+			},
+		},
+	}
+}
+
+func (p *ValidatorPool) Get() *Validator {
+	item := p.pool.Get().(*pooledValidator)
+	if item.ignoreDuration > 0 {
+		item.validator.IgnoreDuplicatesFor(item.ignoreDuration)
+	}
+	return item.validator
+}
+
+func (p *ValidatorPool) Put(v *Validator) {
+	v.mutex.RLock()
+	duration := v.ignoreDuration
+	v.mutex.RUnlock()
+	v.StopIgnoringDuplicates()
+	p.pool.Put(&pooledValidator{
+		validator:      v,
+		ignoreDuration: duration,
+	})
+}
@@ -0,0 +1,48 @@
+package validator
+
+import (
+	"testing"
+	"time"
+)
+
+func TestValidatorPool(t *testing.T) {
+	built := 0
+	pool := NewValidatorPool(func() *Validator {
+		built++
+		return NewValidator().StartsWith("a").IgnoreDuplicatesFor(time.Minute)
+	})
+
+	validator := pool.Get()
+	if !validator.Validate("aaa").Approval {
+		t.Fatal("approval expected")
+	}
+
+	if validator.Validate("aaa").Approval {
+		t.Fatal("deny expected")
+	}
+
+	pool.Put(validator)
+
+	if validator.cleaning {
+		t.Fatal("stopped cleanup expected")
+	}
+
+	validator = pool.Get()
+	defer validator.StopIgnoringDuplicates()
+
+	if !validator.Validate("aaa").Approval {
+		t.Fatal("approval expected")
+	}
+
+	if validator.Validate("aaa").Approval {
+		t.Fatal("deny expected")
+	}
+
+	if validator.Validate("bbb").Approval {
+		t.Fatal("deny expected")
+	}
+
+	if built < 1 {
+		t.Fatal("build expected")
+	}
+}
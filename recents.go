@@ -0,0 +1,53 @@
+package validator
+
+import "container/heap"
+
+type recent struct {
+	text    string
+	expires int64
+}
+
+// recentHeap orders the tracked inputs by expiry, so the oldest one is
+// always on top. Entries whose input was tracked again later are stale and
+// skipped when popped.
+type recentHeap []recent
+
+func (h recentHeap) Len() int           { return len(h) }
+func (h recentHeap) Less(i, j int) bool { return h[i].expires < h[j].expires }
+func (h recentHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+
+func (h *recentHeap) Push(x interface{}) {
+	*h = append(*h, x.(recent))
+}
+
+func (h *recentHeap) Pop() interface{} {
+	old := *h
+	last := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return last
+}
+
+func (v *Validator) trackRecent(text string, expires int64) {
+	v.recents[text] = expires
+	heap.Push(&v.expiries, recent{text: text, expires: expires})
+}
+
+func (v *Validator) popRecent() {
+	oldest := heap.Pop(&v.expiries).(recent)
+	if expires, found := v.recents[oldest.text]; found && expires == oldest.expires {
+		delete(v.recents, oldest.text)
+	}
+}
+
+func (v *Validator) expireRecents(now int64) {
+	for len(v.expiries) > 0 && now > v.expiries[0].expires {
+		v.popRecent()
+	}
+}
+
+func (v *Validator) evictRecent(now int64) {
+	v.expireRecents(now)
+	for len(v.recents) >= v.maxRecents && len(v.expiries) > 0 {
+		v.popRecent()
+	}
+}
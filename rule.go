@@ -1,31 +1,257 @@
 package validator
 
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
 type RuleType string
 
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
 const (
-	StartsWith         = "startsWith"
-	EndsWith           = "endsWith"
-	LongerThan         = "longerThan"
-	LongerThanOrEqual  = "longerThanOrEqual"
-	ShorterThan        = "shorterThan"
-	ShorterThanOrEqual = "shorterThanOrEqual"
-	Contains           = "contains"
-	ContainsACharacter = "containsACharacter"
-	ContainsANumber    = "containsANumber"
-	Ignore             = "ignore"
-	IgnoreDuplicates   = "ignoreDuplicates"
-	Regexp             = "regexp"
-	Custom             = "custom"
+	NotEmpty              = "notEmpty"
+	Required              = "required"
+	StartsWith            = "startsWith"
+	EndsWith              = "endsWith"
+	StartsWithAny         = "startsWithAny"
+	EndsWithAny           = "endsWithAny"
+	StartsWithFold        = "startsWithFold"
+	EndsWithFold          = "endsWithFold"
+	NotStartsWith         = "notStartsWith"
+	NotEndsWith           = "notEndsWith"
+	LongerThan            = "longerThan"
+	LongerThanOrEqual     = "longerThanOrEqual"
+	ShorterThan           = "shorterThan"
+	ShorterThanOrEqual    = "shorterThanOrEqual"
+	MinLength             = "minLength"
+	MaxLength             = "maxLength"
+	LengthEqual           = "lengthEqual"
+	LengthDivisibleBy     = "lengthDivisibleBy"
+	LongerThanBytes       = "longerThanBytes"
+	ShorterThanBytes      = "shorterThanBytes"
+	Contains              = "contains"
+	ContainsFold          = "containsFold"
+	ContainsWord          = "containsWord"
+	ContainsAny           = "containsAny"
+	ContainsAll           = "containsAll"
+	ContainsAtLeast       = "containsAtLeast"
+	ContainsExactly       = "containsExactly"
+	NotContains           = "notContains"
+	NotContainsAny        = "notContainsAny"
+	BlockList             = "blockList"
+	BlockListFold         = "blockListFold"
+	ContainsACharacter    = "containsACharacter"
+	ContainsANumber       = "containsANumber"
+	IsNumeric             = "isNumeric"
+	NumericRange          = "numericRange"
+	ValueDivisibleBy      = "valueDivisibleBy"
+	IsAlphanumeric        = "isAlphanumeric"
+	IsUppercase           = "isUppercase"
+	IsLowercase           = "isLowercase"
+	NoWhitespace          = "noWhitespace"
+	Trimmed               = "trimmed"
+	OnlyScript            = "onlyScript"
+	NoControlCharacters   = "noControlCharacters"
+	IsPrintableASCII      = "isPrintableASCII"
+	RunesInRange          = "runesInRange"
+	EqualTo               = "equalTo"
+	NotEqualTo            = "notEqualTo"
+	EqField               = "eqfield"
+	LexBetween            = "lexBetween"
+	LexBetweenExclusive   = "lexBetweenExclusive"
+	Ignore                = "ignore"
+	IgnoreFold            = "ignoreFold"
+	IgnoreDuplicates      = "ignoreDuplicates"
+	Canceled              = "canceled"
+	DenyByDefault         = "denyByDefault"
+	RequireAtLeastOneRule = "requireAtLeastOneRule"
+	OneOf                 = "oneOf"
+	OneOfFold             = "oneOfFold"
+	Regexp                = "regexp"
+	MatchesOneOf          = "matchesOneOf"
+	Custom                = "custom"
+	Not                   = "not"
+	Any                   = "any"
+	Or                    = "or"
+	When                  = "when"
+	Email                 = "email"
+	EmailStrict           = "emailStrict"
+	EmailWithName         = "emailWithName"
+	URL                   = "url"
+	IsHostname            = "isHostname"
+	IsFQDN                = "isFQDN"
+	IsIP                  = "isIP"
+	IsIPv4                = "isIPv4"
+	IsIPv6                = "isIPv6"
+	IsMAC                 = "isMAC"
+	Date                  = "date"
+	DateBetween           = "dateBetween"
+	IsDuration            = "isDuration"
+	DurationBetween       = "durationBetween"
+	IsJSON                = "isJSON"
+	IsHex                 = "isHex"
+	IsHexBytes            = "isHexBytes"
+	IsBase64              = "isBase64"
+	IsBase64URL           = "isBase64URL"
+	IsUUID                = "isUUID"
+	IsUUIDVersion         = "isUUIDVersion"
+	IsCreditCard          = "isCreditCard"
+	IsCreditCardBrand     = "isCreditCardBrand"
+	IsSlug                = "isSlug"
+	IsSlugUnderscore      = "isSlugUnderscore"
+	IsGoIdentifier        = "isGoIdentifier"
+	IsPhone               = "isPhone"
+	IsSemVer              = "isSemVer"
+	SemVerAtLeast         = "semVerAtLeast"
+	PasswordStrength      = "passwordStrength"
 )
 
 type Rule struct {
 	reason   string
 	ruleType RuleType
+	label    string
+	message  string
+	cost     int
+	severity Severity
+	disabled bool
+	scopes   [][2]int
+	language string
+	args     []interface{}
 	function func(input string) bool
+	bytes    func(input []byte) bool
+	dynamic  func(input string) (bool, string)
+	bounded  func(ctx context.Context, input string) (bool, string)
+	detail   func(input string) string
 }
 
 type Result struct {
-	Approval bool
+	Approval   bool          `json:"approved"`
+	RuleType   RuleType      `json:"ruleType,omitempty"`
+	Label      string        `json:"label,omitempty"`
+	Reason     string        `json:"reason,omitempty"`
+	Severity   Severity      `json:"severity,omitempty"`
+	Failures   []RuleType    `json:"failures,omitempty"`
+	RetryAfter time.Duration `json:"retryAfter,omitempty"`
+}
+
+var defaultCosts = map[RuleType]int{
+	Regexp: 10,
+	Any:    10,
+	Or:     10,
+	Not:    10,
+	When:   10,
+	Custom: 100,
+}
+
+func (r *Rule) estimatedCost() int {
+	if r.cost != 0 {
+		return r.cost
+	}
+	if cost, found := defaultCosts[r.ruleType]; found {
+		return cost
+	}
+	return 1
+}
+
+func (r *Rule) evaluate(ctx context.Context, input string, redact bool) *Result {
+	if r.bounded != nil {
+		approved, reason := r.bounded(ctx, input)
+		if approved {
+			return nil
+		}
+		return r.deny(input, reason, redact)
+	}
+	if r.dynamic != nil {
+		approved, reason := r.dynamic(input)
+		if approved {
+			return nil
+		}
+		return r.deny(input, reason, redact)
+	}
+	if r.function(input) {
+		return nil
+	}
+	reason := notMet(r.reason, input)
+	if r.detail != nil && !redact {
+		reason = fmt.Sprintf("%s (%s)", reason, r.detail(input))
+	}
+	return r.deny(input, reason, redact)
+}
+
+func notMet(reason string, input string) string {
+	return fmt.Sprintf("\"%s\" is not met by \"%s\"", reason, input)
+}
+
+func redacted(reason string, input string) string {
+	reason = strings.ReplaceAll(reason, fmt.Sprintf(" is not met by \"%s\"", input), " is not met")
+	if input == "" {
+		return reason
+	}
+	return strings.ReplaceAll(reason, fmt.Sprintf("\"%s\"", input), "\"[redacted]\"")
+}
+
+func (r *Rule) deny(input string, reason string, redact bool) *Result {
+	switch {
+	case r.message != "" && redact:
+		reason = strings.ReplaceAll(r.message, "{input}", "[redacted]")
+	case r.message != "":
+		reason = strings.ReplaceAll(r.message, "{input}", input)
+	case redact:
+		reason = redacted(reason, input)
+	}
+	return &Result{
+		Approval: false,
+		RuleType: r.ruleType,
+		Label:    r.label,
+		Reason:   reason,
+		Severity: r.level(),
+	}
+}
+
+func (r *Rule) level() Severity {
+	if r.severity == "" {
+		return SeverityError
+	}
+	return r.severity
+}
+
+func (r *Result) IsWarning() bool {
+	return r.Severity == SeverityWarning
+}
+
+type RuleOutcome struct {
 	RuleType RuleType
+	Label    string
+	Passed   bool
 	Reason   string
+	Duration time.Duration
+}
+
+type ValidationError struct {
+	RuleType RuleType
+	Label    string
+	Reason   string
+}
+
+func (e *ValidationError) Error() string {
+	return e.Reason
+}
+
+func (r *Result) AsError() error {
+	if r.Approval {
+		return nil
+	}
+	return &ValidationError{
+		RuleType: r.RuleType,
+		Label:    r.Label,
+		Reason:   r.Reason,
+	}
 }
@@ -0,0 +1,83 @@
+package validator
+
+import (
+	"bufio"
+	"io"
+	"sync"
+)
+
+type LineResult struct {
+	Line   int
+	Input  string
+	Result *Result
+	Err    error
+}
+
+func (v *Validator) ValidateReader(r io.Reader) <-chan LineResult {
+	results := make(chan LineResult)
+	go func() {
+		defer close(results)
+		scanner := bufio.NewScanner(r)
+		line := 0
+		for scanner.Scan() {
+			line++
+			input := scanner.Text()
+			results <- LineResult{
+				Line:   line,
+				Input:  input,
+				Result: v.Validate(input),
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			results <- LineResult{
+				Line: line + 1,
+				Err:  err,
+			}
+		}
+	}()
+	return results
+}
+
+// ValidateAsync validates the inputs received from the channel on the given
+// number of workers and emits one result per input in the order they arrived.
+// The returned channel is closed once inputs is closed and drained. Repeated
+// inputs behave as in ValidateBatchParallel.
+func (v *Validator) ValidateAsync(inputs <-chan string, workers int) <-chan *Result {
+	if workers < 1 {
+		workers = 1
+	}
+	type job struct {
+		input  string
+		result chan *Result
+	}
+	jobs := make(chan job)
+	pending := make(chan chan *Result, workers)
+	wg := sync.WaitGroup{}
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				j.result <- v.Validate(j.input)
+			}
+		}()
+	}
+	go func() {
+		defer close(pending)
+		defer close(jobs)
+		for input := range inputs {
+			result := make(chan *Result, 1)
+			pending <- result
+			jobs <- job{input: input, result: result}
+		}
+	}()
+	results := make(chan *Result)
+	go func() {
+		defer close(results)
+		for result := range pending {
+			results <- <-result
+		}
+		wg.Wait()
+	}()
+	return results
+}
@@ -0,0 +1,107 @@
+package validator
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestValidateReader(t *testing.T) {
+	validator := NewValidator().StartsWith("a").IgnoreDuplicatesFor(time.Minute)
+	defer validator.StopIgnoringDuplicates()
+
+	input := strings.NewReader("aaa\nbbb\naaa\nabc")
+	expected := []bool{true, false, false, true}
+
+	line := 0
+	for result := range validator.ValidateReader(input) {
+		if result.Err != nil {
+			t.Fatal(result.Err)
+		}
+
+		if result.Line != line+1 {
+			t.Fatal("invalid line", result.Line, line+1)
+		}
+
+		if result.Result.Approval != expected[line] {
+			t.Fatal("invalid approval", result.Input, result.Result.Approval, expected[line])
+		}
+		line++
+	}
+
+	if line != len(expected) {
+		t.Fatal("invalid line count", line, len(expected))
+	}
+}
+
+type failingReader struct{}
+
+func (failingReader) Read(p []byte) (int, error) {
+	return 0, errors.New("read failed")
+}
+
+func TestValidateReaderError(t *testing.T) {
+	var last LineResult
+	for result := range NewValidator().ValidateReader(failingReader{}) {
+		last = result
+	}
+
+	if last.Err == nil {
+		t.Fatal("error expected")
+	}
+}
+
+func TestValidateAsync(t *testing.T) {
+	validator := NewValidator().Custom("even length", func(input string) bool {
+		time.Sleep(time.Duration(len(input)) * time.Millisecond)
+		return len(input)%2 == 0
+	})
+
+	inputs := []string{"aaaaaa", "a", "aaaa", "aaa", "aa", "", "aaaaa"}
+
+	for _, workers := range []int{0, 1, 4} {
+		channel := make(chan string)
+		go func() {
+			defer close(channel)
+			for _, input := range inputs {
+				channel <- input
+			}
+		}()
+
+		i := 0
+		for result := range validator.ValidateAsync(channel, workers) {
+			expected := len(inputs[i])%2 == 0
+			if result.Approval != expected {
+				t.Fatal("invalid result order", workers, i, inputs[i])
+			}
+			i++
+		}
+
+		if i != len(inputs) {
+			t.Fatal("invalid result count", workers, i)
+		}
+	}
+}
+
+func TestValidateAsyncDuplicates(t *testing.T) {
+	validator := NewValidator(WithDedup(time.Hour)).NotEmpty()
+	defer validator.StopIgnoringDuplicates()
+
+	channel := make(chan string, 100)
+	for i := 0; i < 100; i++ {
+		channel <- "abc"
+	}
+	close(channel)
+
+	approvals := 0
+	for result := range validator.ValidateAsync(channel, 8) {
+		if result.Approval {
+			approvals++
+		}
+	}
+
+	if approvals != 1 {
+		t.Fatal("exactly one approval expected", approvals)
+	}
+}
@@ -0,0 +1,162 @@
+package validator
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+type structField struct {
+	name      string
+	index     int
+	validator *Validator
+	equals    []string
+}
+
+var structFields sync.Map
+
+// ValidateStruct validates the exported string fields of s, or of the struct
+// s points to, using their validate tags, e.g. `validate:"startsWith=abc,longerThan=4"`.
+// Every rule of the tag is named by its RuleType and takes its parameters
+// after an equals sign, separated by spaces. The eqfield rule compares the
+// field to the named sibling field, e.g. `validate:"eqfield=Password"`. Only
+// failing fields are present in the returned map. A comma only separates
+// rules when a known rule name follows it, so parameters like the regexp
+// ^a{1,3}$ can contain commas.
+func ValidateStruct(s interface{}) (map[string][]*Result, error) {
+	value := reflect.ValueOf(s)
+	for value.Kind() == reflect.Ptr {
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("expected a struct, got %s", value.Kind())
+	}
+	fields, err := fieldsOf(value.Type())
+	if err != nil {
+		return nil, err
+	}
+	failures := map[string][]*Result{}
+	for _, field := range fields {
+		input := value.Field(field.index).String()
+		results := field.validator.ValidateAll(input)
+		for _, other := range field.equals {
+			if input != value.FieldByName(other).String() {
+				results = append(results, &Result{
+					Approval: false,
+					RuleType: EqField,
+					Reason:   fmt.Sprintf("%s must be equal to %s", field.name, other),
+					Severity: SeverityError,
+				})
+			}
+		}
+		if len(results) > 0 {
+			failures[field.name] = results
+		}
+	}
+	return failures, nil
+}
+
+func fieldsOf(t reflect.Type) ([]structField, error) {
+	if cached, found := structFields.Load(t); found {
+		return cached.([]structField), nil
+	}
+	fields := []structField{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag, found := field.Tag.Lookup("validate")
+		if !found || field.PkgPath != "" {
+			continue
+		}
+		if field.Type.Kind() != reflect.String {
+			return nil, fmt.Errorf("field %s: expected a string, got %s", field.Name, field.Type.Kind())
+		}
+		validator, equals, err := parseTag(tag)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", field.Name, err)
+		}
+		for _, other := range equals {
+			if sibling, found := t.FieldByName(other); !found || sibling.Type.Kind() != reflect.String {
+				return nil, fmt.Errorf("field %s: eqfield %s is not a string field", field.Name, other)
+			}
+		}
+		fields = append(fields, structField{name: field.Name, index: i, validator: validator, equals: equals})
+	}
+	structFields.Store(t, fields)
+	return fields, nil
+}
+
+func parseTag(tag string) (*Validator, []string, error) {
+	v := NewValidator()
+	equals := []string{}
+	for _, part := range splitTag(tag) {
+		name, param, _ := strings.Cut(part, "=")
+		if name == EqField {
+			equals = append(equals, param)
+			continue
+		}
+		build, found := ruleBuilders[RuleType(name)]
+		if !found {
+			return nil, nil, fmt.Errorf("unknown rule %s", name)
+		}
+		if err := buildFromTag(v, build, param); err != nil {
+			return nil, nil, fmt.Errorf("rule %s: %w", name, err)
+		}
+	}
+	return v, equals, v.Err()
+}
+
+func splitTag(tag string) []string {
+	parts := []string{}
+	for _, part := range strings.Split(tag, ",") {
+		if part == "" {
+			continue
+		}
+		name, _, _ := strings.Cut(part, "=")
+		_, known := ruleBuilders[RuleType(name)]
+		if !known && name != EqField && len(parts) > 0 && strings.Contains(parts[len(parts)-1], "=") {
+			parts[len(parts)-1] += "," + part
+			continue
+		}
+		parts = append(parts, part)
+	}
+	return parts
+}
+
+func buildFromTag(v *Validator, build ruleBuilder, param string) error {
+	if param == "" {
+		if build(v, nil) == nil {
+			return nil
+		}
+		return build(v, []json.RawMessage{json.RawMessage("[]")})
+	}
+	params := strings.Fields(param)
+	texts := make([]json.RawMessage, len(params))
+	values := make([]json.RawMessage, len(params))
+	for i, p := range params {
+		texts[i] = quote(p)
+		if json.Valid([]byte(p)) {
+			values[i] = json.RawMessage(p)
+		} else {
+			values[i] = texts[i]
+		}
+	}
+	list, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+	err = build(v, []json.RawMessage{quote(param)})
+	for _, args := range [][]json.RawMessage{values, {list}} {
+		if err == nil {
+			break
+		}
+		err = build(v, args)
+	}
+	return err
+}
+
+func quote(text string) json.RawMessage {
+	data, _ := json.Marshal(text)
+	return data
+}
@@ -0,0 +1,146 @@
+package validator
+
+import (
+	"testing"
+)
+
+type signup struct {
+	Username string `validate:"startsWith=abc,longerThan=4"`
+	Country  string `validate:"oneOf=HU DE US"`
+	Age      string `validate:"numericRange=18 130"`
+	Website  string `validate:"url"`
+	Nickname string
+	internal string `validate:"notEmpty"`
+}
+
+func TestValidateStruct(t *testing.T) {
+	failures, err := ValidateStruct(&signup{
+		Username: "abcde",
+		Country:  "HU",
+		Age:      "30",
+		Website:  "https://example.com",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(failures) != 0 {
+		t.Fatal("no failures expected", failures)
+	}
+
+	failures, err = ValidateStruct(signup{
+		Username: "xyz",
+		Country:  "FR",
+		Age:      "12",
+		Website:  "example",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string][]RuleType{
+		"Username": {StartsWith, LongerThan},
+		"Country":  {OneOf},
+		"Age":      {NumericRange},
+		"Website":  {URL},
+	}
+
+	if len(failures) != len(expected) {
+		t.Fatal("invalid failure count", failures)
+	}
+
+	for field, ruleTypes := range expected {
+		if len(failures[field]) != len(ruleTypes) {
+			t.Fatal("invalid failures", field, failures[field])
+		}
+
+		for i, ruleType := range ruleTypes {
+			if failures[field][i].RuleType != ruleType {
+				t.Fatal("invalid rule type", field, failures[field][i].RuleType, ruleType)
+			}
+		}
+	}
+}
+
+type passwordChange struct {
+	Password     string `validate:"longerThan=7"`
+	Confirmation string `validate:"eqfield=Password"`
+}
+
+func TestValidateStructEqField(t *testing.T) {
+	failures, err := ValidateStruct(passwordChange{Password: "secret123", Confirmation: "secret123"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(failures) != 0 {
+		t.Fatal("no failures expected", failures)
+	}
+
+	failures, err = ValidateStruct(passwordChange{Password: "secret123", Confirmation: "secret124"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	results := failures["Confirmation"]
+	if len(failures) != 1 || len(results) != 1 {
+		t.Fatal("invalid failures", failures)
+	}
+
+	if results[0].RuleType != EqField || results[0].Reason != "Confirmation must be equal to Password" {
+		t.Fatal("invalid result", results[0])
+	}
+}
+
+func TestValidateStructCommaInParameter(t *testing.T) {
+	type code struct {
+		Value string `validate:"regexp=^a{1,3}$,notEmpty"`
+	}
+
+	for input, valid := range map[string]bool{"a": true, "aaa": true, "aaaa": false, "": false} {
+		failures, err := ValidateStruct(code{Value: input})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if (len(failures) == 0) != valid {
+			t.Fatal("invalid failures", input, failures)
+		}
+	}
+}
+
+func TestValidateStructInvalid(t *testing.T) {
+	var tests = []struct {
+		name  string
+		value interface{}
+	}{
+		{name: "NotStruct", value: "abc"},
+		{name: "UnknownRule", value: struct {
+			Field string `validate:"unknown"`
+		}{}},
+		{name: "InvalidParameter", value: struct {
+			Field string `validate:"longerThan=abc"`
+		}{}},
+		{name: "InvalidRegexp", value: struct {
+			Field string `validate:"regexp=[0-9]++"`
+		}{}},
+		{name: "UnknownEqField", value: struct {
+			Field string `validate:"eqfield=Missing"`
+		}{}},
+		{name: "NonStringEqField", value: struct {
+			Field string `validate:"eqfield=Other"`
+			Other int
+		}{}},
+		{name: "NotString", value: struct {
+			Field int `validate:"notEmpty"`
+		}{}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := ValidateStruct(test.value); err == nil {
+				t.Fatal("error expected")
+			}
+		})
+	}
+}
@@ -1,242 +1,2699 @@
 package validator
 
 import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"go/token"
+	"net"
+	"net/mail"
+	"net/url"
+	"os"
 	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
+	"golang.org/x/text/unicode/norm"
 )
 
 type Validator struct {
 	rules          []*Rule
 	ignoreDuration time.Duration
 	recents        map[string]int64
+	expiries       recentHeap
 	mutex          sync.RWMutex
 	close          chan struct{}
+	cleaning       bool
+	maxRecents     int
+	denyByDefault  bool
+	requireRules   bool
+	redact         bool
+	stats          map[RuleType]int64
+	aggregate      bool
+	preprocess     func(input string) string
+	onEvaluate     func(ruleType RuleType, input string, passed bool)
+	language       language.Tag
+	err            error
+	now            func() time.Time
+}
+
+type Option func(v *Validator)
+
+func WithDedup(duration time.Duration) Option {
+	return func(v *Validator) {
+		v.IgnoreDuplicatesFor(duration)
+	}
+}
+
+func WithClock(now func() time.Time) Option {
+	return func(v *Validator) {
+		v.WithClock(now)
+	}
 }
 
-func NewValidator() *Validator {
-	return &Validator{
+func WithFoldLanguage(tag language.Tag) Option {
+	return func(v *Validator) {
+		v.FoldLanguage(tag)
+	}
+}
+
+func WithMaxRecents(maxEntries int) Option {
+	return func(v *Validator) {
+		v.maxRecents = maxEntries
+	}
+}
+
+func NewValidator(opts ...Option) *Validator {
+	v := &Validator{
 		rules:          []*Rule{},
 		ignoreDuration: 0,
 		recents:        make(map[string]int64),
 		mutex:          sync.RWMutex{},
 		close:          make(chan struct{}),
+		now:            time.Now,
 	}
+	for _, opt := range opts {
+		opt(v)
+	}
+	return v
 }
 
 func (v *Validator) Validate(input string) *Result {
-	for _, r := range v.rules {
-		if !r.function(input) {
-			return &Result{
-				Approval: false,
-				RuleType: r.ruleType,
-				Reason:   fmt.Sprintf("\"%s\" is not met by \"%s\"", r.reason, input),
+	return v.ValidateContext(context.Background(), input)
+}
+
+func (v *Validator) ValidateContext(ctx context.Context, input string) *Result {
+	failFast := v.failsFast()
+
+	failures, _ := PartitionBySeverity(v.evaluate(ctx, input, failFast))
+	if len(failures) == 0 {
+		return &Result{
+			Approval: true,
+		}
+	}
+	if failFast {
+		return failures[0]
+	}
+	reasons := make([]string, len(failures))
+	ruleTypes := make([]RuleType, len(failures))
+	var retryAfter time.Duration
+	for i, failure := range failures {
+		reasons[i] = failure.Reason
+		ruleTypes[i] = failure.RuleType
+		if failure.RetryAfter > retryAfter {
+			retryAfter = failure.RetryAfter
+		}
+	}
+	return &Result{
+		Approval:   false,
+		RuleType:   failures[0].RuleType,
+		Label:      failures[0].Label,
+		Reason:     strings.Join(reasons, "; "),
+		Severity:   SeverityError,
+		Failures:   ruleTypes,
+		RetryAfter: retryAfter,
+	}
+}
+
+// ValidateValue validates value by its fmt.Sprint representation, which is
+// the String method for a fmt.Stringer. The rules of v only ever see that
+// string, so numeric rules like NumericRange apply to its formatting.
+func ValidateValue[T any](v *Validator, value T) *Result {
+	return v.Validate(fmt.Sprint(value))
+}
+
+func (v *Validator) IsValid(input string) bool {
+	input = v.prepare(input)
+	return v.rulesPass(input) && v.checkDuplicate(input) == nil
+}
+
+// ValidateBytes validates b without converting it to a string when every rule
+// has a byte slice implementation. Rules without one, such as Custom, see
+// string(b), which is converted once per call and only when first needed.
+// Validators with preprocessing, deny by default, required rules, redaction,
+// aggregated failures, an evaluation hook or statistics always validate
+// string(b).
+func (v *Validator) ValidateBytes(b []byte) *Result {
+	v.mutex.RLock()
+	fallback := v.preprocess != nil || v.denyByDefault || v.requireRules || v.redact || v.aggregate || v.onEvaluate != nil || v.stats != nil
+	duration := v.ignoreDuration
+	v.mutex.RUnlock()
+	if fallback {
+		return v.Validate(string(b))
+	}
+	var input string
+	converted := false
+	for _, r := range v.snapshot() {
+		if r.disabled || r.severity == SeverityWarning {
+			continue
+		}
+		if r.bytes != nil && r.dynamic == nil {
+			if !r.bytes(b) {
+				return r.deny(string(b), notMet(r.reason, string(b)), false)
 			}
+			continue
+		}
+		if !converted {
+			input, converted = string(b), true
+		}
+		if result := r.evaluate(context.Background(), input, false); result != nil {
+			return result
+		}
+	}
+	if duration > 0 {
+		if result := v.checkDuplicate(string(b)); result != nil {
+			return result
+		}
+	}
+	return &Result{
+		Approval: true,
+	}
+}
+
+func (v *Validator) ValidateAll(input string) []*Result {
+	return v.evaluate(context.Background(), input, false)
+}
+
+// ValidateVerbose runs every enabled rule against input and reports how each one
+// did and how long it took. It is meant for debugging and does not take part
+// in duplicate tracking.
+func (v *Validator) ValidateVerbose(input string) []RuleOutcome {
+	input = v.prepare(input)
+	outcomes := []RuleOutcome{}
+	redact := v.redacts()
+	for _, r := range v.snapshot() {
+		if r.disabled {
+			continue
+		}
+		start := time.Now()
+		result := r.evaluate(context.Background(), input, redact)
+		outcome := RuleOutcome{
+			RuleType: r.ruleType,
+			Label:    r.label,
+			Passed:   result == nil,
+			Duration: time.Since(start),
 		}
+		if result != nil {
+			outcome.Reason = result.Reason
+		}
+		outcomes = append(outcomes, outcome)
 	}
-	if v.ignoreDuration > 0 {
-		v.mutex.RLock()
-		_, found := v.recents[input]
-		v.mutex.RUnlock()
-		if found {
-			return &Result{
+	return outcomes
+}
+
+func (v *Validator) FailFast(failFast bool) *Validator {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+	v.aggregate = !failFast
+	return v
+}
+
+func (v *Validator) failsFast() bool {
+	v.mutex.RLock()
+	defer v.mutex.RUnlock()
+	return !v.aggregate
+}
+
+func (v *Validator) evaluate(ctx context.Context, input string, failFast bool) []*Result {
+	results := v.collect(ctx, input, failFast)
+	v.record(results)
+	return results
+}
+
+func (v *Validator) collect(ctx context.Context, input string, failFast bool) []*Result {
+	input = v.prepare(input)
+	results := []*Result{}
+	rules, denyByDefault, hook := v.snapshot(), v.deniesByDefault(), v.hook()
+	redact := v.redacts()
+	for _, r := range rules {
+		if err := ctx.Err(); err != nil {
+			return append(results, &Result{
 				Approval: false,
-				RuleType: IgnoreDuplicates,
-				Reason:   "ignore duplication",
+				RuleType: Canceled,
+				Reason:   err.Error(),
+				Severity: SeverityError,
+			})
+		}
+		if r.disabled || (denyByDefault && r.ruleType == Any) {
+			continue
+		}
+		result := r.evaluate(ctx, input, redact)
+		if hook != nil {
+			hook(r.ruleType, input, result == nil)
+		}
+		if result != nil {
+			results = append(results, result)
+			if failFast && !result.IsWarning() {
+				return distinct(results)
+			}
+		}
+	}
+	if denyByDefault && !allowedBy(rules, input) {
+		result := denyByDefaultResult(input)
+		if redact {
+			result.Reason = redacted(result.Reason, input)
+		}
+		results = append(results, result)
+	}
+	if v.requiresRules() && !hasEnabled(rules) {
+		results = append(results, noRulesResult())
+	}
+	if failures, _ := PartitionBySeverity(results); len(failures) > 0 {
+		return distinct(results)
+	}
+	if result := v.checkDuplicate(input); result != nil {
+		results = append(results, result)
+	}
+	return distinct(results)
+}
+
+func distinct(results []*Result) []*Result {
+	type key struct {
+		ruleType RuleType
+		label    string
+		reason   string
+		severity Severity
+	}
+	seen := make(map[key]struct{}, len(results))
+	unique := results[:0:0]
+	for _, result := range results {
+		k := key{result.RuleType, result.Label, result.Reason, result.Severity}
+		if _, found := seen[k]; !found {
+			seen[k] = struct{}{}
+			unique = append(unique, result)
+		}
+	}
+	return unique
+}
+
+func PartitionBySeverity(results []*Result) (failures []*Result, warnings []*Result) {
+	for _, result := range results {
+		if result.IsWarning() {
+			warnings = append(warnings, result)
+		} else {
+			failures = append(failures, result)
+		}
+	}
+	return failures, warnings
+}
+
+func (v *Validator) ValidateBatch(inputs []string) []*Result {
+	results := make([]*Result, len(inputs))
+	for i, input := range inputs {
+		results[i] = v.Validate(input)
+	}
+	return results
+}
+
+// ValidateMap validates every distinct input once. Repeated values within the
+// same call share a single result, so duplicate tracking only denies values
+// seen in earlier validations.
+func (v *Validator) ValidateMap(inputs []string) map[string]*Result {
+	results := make(map[string]*Result, len(inputs))
+	for _, input := range inputs {
+		if _, found := results[input]; !found {
+			results[input] = v.Validate(input)
+		}
+	}
+	return results
+}
+
+// ValidateBatchParallel validates the inputs on the given number of workers.
+// Duplicate tracking is shared with Validate, so when the batch contains the
+// same input more than once it is not defined which occurrence is approved.
+func (v *Validator) ValidateBatchParallel(inputs []string, workers int) []*Result {
+	if workers < 2 {
+		return v.ValidateBatch(inputs)
+	}
+
+	results := make([]*Result, len(inputs))
+	indexes := make(chan int)
+	wg := sync.WaitGroup{}
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				results[i] = v.Validate(inputs[i])
+			}
+		}()
+	}
+	for i := range inputs {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+	return results
+}
+
+func (v *Validator) Rules() []RuleType {
+	rules := v.snapshot()
+	ruleTypes := make([]RuleType, len(rules))
+	for i, r := range rules {
+		ruleTypes[i] = r.ruleType
+	}
+	return ruleTypes
+}
+
+func (v *Validator) RuleCount() int {
+	return len(v.snapshot())
+}
+
+func (v *Validator) String() string {
+	return fmt.Sprintf("[%s]", strings.Join(v.reasons(), " AND "))
+}
+
+func (v *Validator) Err() error {
+	v.mutex.RLock()
+	defer v.mutex.RUnlock()
+	return v.err
+}
+
+func (v *Validator) setErr(err error) {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+	if v.err == nil {
+		v.err = err
+	}
+}
+
+func (v *Validator) add(rule *Rule) *Validator {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+	v.rules = append(v.rules, rule)
+	return v
+}
+
+func (v *Validator) Labeled(label string) *Validator {
+	return v.updateLast(func(r *Rule) {
+		r.label = label
+	})
+}
+
+func (v *Validator) RemoveRule(ruleType RuleType) *Validator {
+	return v.removeRules(func(r *Rule) bool {
+		return r.ruleType == ruleType
+	})
+}
+
+func (v *Validator) RemoveRuleByLabel(label string) *Validator {
+	return v.removeRules(func(r *Rule) bool {
+		return r.label == label
+	})
+}
+
+func (v *Validator) removeRules(match func(r *Rule) bool) *Validator {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+	rules := []*Rule{}
+	for _, r := range v.rules {
+		if !match(r) {
+			rules = append(rules, r)
+		}
+	}
+	v.rules = rules
+	return v
+}
+
+func (v *Validator) DisableRule(ruleType RuleType) *Validator {
+	return v.setDisabled(true, func(r *Rule) bool {
+		return r.ruleType == ruleType
+	})
+}
+
+func (v *Validator) EnableRule(ruleType RuleType) *Validator {
+	return v.setDisabled(false, func(r *Rule) bool {
+		return r.ruleType == ruleType
+	})
+}
+
+func (v *Validator) DisableRuleByLabel(label string) *Validator {
+	return v.setDisabled(true, func(r *Rule) bool {
+		return r.label == label
+	})
+}
+
+func (v *Validator) EnableRuleByLabel(label string) *Validator {
+	return v.setDisabled(false, func(r *Rule) bool {
+		return r.label == label
+	})
+}
+
+func (v *Validator) setDisabled(disabled bool, match func(r *Rule) bool) *Validator {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+	rules := make([]*Rule, len(v.rules))
+	for i, r := range v.rules {
+		rules[i] = r
+		if match(r) && r.disabled != disabled {
+			updated := *r
+			updated.disabled = disabled
+			rules[i] = &updated
+		}
+	}
+	v.rules = rules
+	return v
+}
+
+func (v *Validator) WithMessage(message string) *Validator {
+	return v.updateLast(func(r *Rule) {
+		r.message = message
+	})
+}
+
+// Warn marks the last added rule as advisory. Failing warnings are reported by
+// ValidateAll but do not deny an input.
+func (v *Validator) Warn() *Validator {
+	return v.updateLast(func(r *Rule) {
+		r.severity = SeverityWarning
+	})
+}
+
+func (v *Validator) Error() *Validator {
+	return v.updateLast(func(r *Rule) {
+		r.severity = SeverityError
+	})
+}
+
+// Substring scopes the last added rule to the characters from start up to, but
+// not including, end. Inputs shorter than the range are cut where they end.
+func (v *Validator) Substring(start int, end int) *Validator {
+	if start < 0 || end < start {
+		v.setErr(fmt.Errorf("invalid substring range %d to %d", start, end))
+	}
+	return v.updateLast(func(r *Rule) {
+		r.scopes = append(r.scopes[:len(r.scopes):len(r.scopes)], [2]int{start, end})
+		r.reason = fmt.Sprintf("%s in characters %d to %d", r.reason, start, end)
+		r.bytes = nil
+		if function := r.function; function != nil {
+			r.function = func(input string) bool {
+				return function(substring(input, start, end))
+			}
+		}
+		if dynamic := r.dynamic; dynamic != nil {
+			r.dynamic = func(input string) (bool, string) {
+				return dynamic(substring(input, start, end))
+			}
+		}
+		if bounded := r.bounded; bounded != nil {
+			r.bounded = func(ctx context.Context, input string) (bool, string) {
+				return bounded(ctx, substring(input, start, end))
+			}
+		}
+		if detail := r.detail; detail != nil {
+			r.detail = func(input string) string {
+				return detail(substring(input, start, end))
+			}
+		}
+	})
+}
+
+func substring(input string, start int, end int) string {
+	from, to := len(input), len(input)
+	i := 0
+	for position := range input {
+		if i == start {
+			from = position
+		}
+		if i == end {
+			to = position
+			break
+		}
+		i++
+	}
+	if from > to {
+		return ""
+	}
+	return input[from:to]
+}
+
+func (v *Validator) Cost(cost int) *Validator {
+	return v.updateLast(func(r *Rule) {
+		r.cost = cost
+	})
+}
+
+func (v *Validator) SortRulesByCost() *Validator {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+	rules := make([]*Rule, len(v.rules))
+	copy(rules, v.rules)
+	sort.SliceStable(rules, func(i, j int) bool {
+		return rules[i].estimatedCost() < rules[j].estimatedCost()
+	})
+	v.rules = rules
+	return v
+}
+
+func (v *Validator) updateLast(update func(r *Rule)) *Validator {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+	n := len(v.rules)
+	if n == 0 {
+		return v
+	}
+	last := *v.rules[n-1]
+	update(&last)
+	v.rules = append(v.rules[:n-1:n-1], &last)
+	return v
+}
+
+func (v *Validator) snapshot() []*Rule {
+	v.mutex.RLock()
+	defer v.mutex.RUnlock()
+	return v.rules
+}
+
+func (v *Validator) passes(input string) bool {
+	return v.rulesPass(v.prepare(input))
+}
+
+func (v *Validator) rulesPass(input string) bool {
+	rules, denyByDefault, hook := v.snapshot(), v.deniesByDefault(), v.hook()
+	for _, r := range rules {
+		if r.disabled || (denyByDefault && r.ruleType == Any) || r.severity == SeverityWarning {
+			continue
+		}
+		passed := r.function(input)
+		if hook != nil {
+			hook(r.ruleType, input, passed)
+		}
+		if !passed {
+			return false
+		}
+	}
+	if v.requiresRules() && !hasEnabled(rules) {
+		return false
+	}
+	return !denyByDefault || allowedBy(rules, input)
+}
+
+func (v *Validator) Preprocess(fn func(input string) string) *Validator {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+	previous := v.preprocess
+	if previous == nil {
+		v.preprocess = fn
+	} else {
+		v.preprocess = func(input string) string {
+			return fn(previous(input))
+		}
+	}
+	return v
+}
+
+func (v *Validator) TrimSpace() *Validator {
+	return v.Preprocess(strings.TrimSpace)
+}
+
+func (v *Validator) NormalizeUnicode() *Validator {
+	return v.Preprocess(norm.NFC.String)
+}
+
+func (v *Validator) OnEvaluate(hook func(ruleType RuleType, input string, passed bool)) *Validator {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+	previous := v.onEvaluate
+	if previous == nil {
+		v.onEvaluate = hook
+	} else {
+		v.onEvaluate = func(ruleType RuleType, input string, passed bool) {
+			previous(ruleType, input, passed)
+			hook(ruleType, input, passed)
+		}
+	}
+	return v
+}
+
+func (v *Validator) hook() func(ruleType RuleType, input string, passed bool) {
+	v.mutex.RLock()
+	defer v.mutex.RUnlock()
+	return v.onEvaluate
+}
+
+func (v *Validator) NormalizeMAC() *Validator {
+	return v.Preprocess(func(input string) string {
+		if addr, ok := parseMAC(input); ok {
+			return addr.String()
+		}
+		return input
+	})
+}
+
+func (v *Validator) prepare(input string) string {
+	v.mutex.RLock()
+	preprocess := v.preprocess
+	v.mutex.RUnlock()
+	if preprocess == nil {
+		return input
+	}
+	return preprocess(input)
+}
+
+// DenyByDefault turns the Any rules of the validator into an allowlist: an
+// input is approved only when at least one of them matches, and a validator
+// without Any rules denies everything. All other rules are still ANDed.
+func (v *Validator) DenyByDefault() *Validator {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+	v.denyByDefault = true
+	return v
+}
+
+func (v *Validator) deniesByDefault() bool {
+	v.mutex.RLock()
+	defer v.mutex.RUnlock()
+	return v.denyByDefault
+}
+
+// TrackStats makes the validator count the denials of every rule type, which
+// Stats reports. Validate, ValidateContext and ValidateAll are counted.
+func (v *Validator) TrackStats() *Validator {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+	if v.stats == nil {
+		v.stats = map[RuleType]int64{}
+	}
+	return v
+}
+
+func (v *Validator) Stats() map[RuleType]int64 {
+	v.mutex.RLock()
+	defer v.mutex.RUnlock()
+	stats := make(map[RuleType]int64, len(v.stats))
+	for ruleType, count := range v.stats {
+		stats[ruleType] = count
+	}
+	return stats
+}
+
+func (v *Validator) ResetStats() *Validator {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+	if v.stats != nil {
+		v.stats = map[RuleType]int64{}
+	}
+	return v
+}
+
+func (v *Validator) record(results []*Result) {
+	v.mutex.RLock()
+	tracking := v.stats != nil
+	v.mutex.RUnlock()
+	if !tracking || len(results) == 0 {
+		return
+	}
+	failures, _ := PartitionBySeverity(results)
+	if len(failures) == 0 {
+		return
+	}
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+	if v.stats == nil {
+		return
+	}
+	for _, result := range failures {
+		v.stats[result.RuleType]++
+	}
+}
+
+// RedactInput keeps the input out of the reasons of failed rules, so
+// `"longer than 4" is not met by "secret"` becomes `"longer than 4" is not met`
+// and {input} in messages is replaced with [redacted].
+func (v *Validator) RedactInput() *Validator {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+	v.redact = true
+	return v
+}
+
+func (v *Validator) redacts() bool {
+	v.mutex.RLock()
+	defer v.mutex.RUnlock()
+	return v.redact
+}
+
+// RequireAtLeastOneRule makes the validator deny every input while it has no
+// enabled rules, instead of approving everything.
+func (v *Validator) RequireAtLeastOneRule() *Validator {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+	v.requireRules = true
+	return v
+}
+
+func (v *Validator) requiresRules() bool {
+	v.mutex.RLock()
+	defer v.mutex.RUnlock()
+	return v.requireRules
+}
+
+func hasEnabled(rules []*Rule) bool {
+	for _, r := range rules {
+		if !r.disabled {
+			return true
+		}
+	}
+	return false
+}
+
+func noRulesResult() *Result {
+	return &Result{
+		Approval: false,
+		RuleType: RequireAtLeastOneRule,
+		Reason:   "validator has no rules",
+		Severity: SeverityError,
+	}
+}
+
+func allowedBy(rules []*Rule, input string) bool {
+	for _, r := range rules {
+		if r.ruleType == Any && !r.disabled && r.function(input) {
+			return true
+		}
+	}
+	return false
+}
+
+func denyByDefaultResult(input string) *Result {
+	return &Result{
+		Approval: false,
+		RuleType: DenyByDefault,
+		Reason:   notMet("allowed by an any rule", input),
+		Severity: SeverityError,
+	}
+}
+
+func (v *Validator) reasons() []string {
+	rules := v.snapshot()
+	reasons := make([]string, len(rules))
+	for i, r := range rules {
+		reasons[i] = r.reason
+	}
+	return reasons
+}
+
+func (v *Validator) checkDuplicate(input string) *Result {
+	v.mutex.RLock()
+	duration := v.ignoreDuration
+	v.mutex.RUnlock()
+	if duration <= 0 {
+		return nil
+	}
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+	now := v.now()
+	duration = v.ignoreDuration
+	if duration <= 0 {
+		return nil
+	}
+	expires, found := v.recents[input]
+	if found && now.UnixNano() <= expires {
+		return &Result{
+			Approval:   false,
+			RuleType:   IgnoreDuplicates,
+			Reason:     "ignore duplication",
+			Severity:   SeverityError,
+			RetryAfter: time.Duration(expires - now.UnixNano()),
+		}
+	}
+	if !found && v.maxRecents > 0 && len(v.recents) >= v.maxRecents {
+		v.evictRecent(now.UnixNano())
+	}
+	v.trackRecent(input, now.Add(duration).UnixNano())
+	return nil
+}
+
+func (v *Validator) Custom(denyReason string, function func(input string) bool) *Validator {
+	return v.add(&Rule{
+		reason:   denyReason,
+		ruleType: Custom,
+		function: function,
+	})
+}
+
+func (v *Validator) CustomWithReason(function func(input string) (bool, string)) *Validator {
+	return v.add(&Rule{
+		reason:   "custom",
+		ruleType: Custom,
+		dynamic:  function,
+		function: func(input string) bool {
+			approved, _ := function(input)
+			return approved
+		},
+	})
+}
+
+// CustomWithTimeout runs function in its own goroutine and denies the input
+// when it does not return within timeout. The context passed to function is
+// derived from the one given to ValidateContext, so an outer deadline or
+// cancellation applies as well.
+func (v *Validator) CustomWithTimeout(denyReason string, timeout time.Duration, function func(ctx context.Context, input string) bool) *Validator {
+	bounded := func(ctx context.Context, input string) (bool, string) {
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+		done := make(chan bool, 1)
+		go func() {
+			done <- function(ctx, input)
+		}()
+		select {
+		case approved := <-done:
+			return approved, notMet(denyReason, input)
+		case <-ctx.Done():
+			return false, fmt.Sprintf("\"%s\" timed out for \"%s\": %v", denyReason, input, ctx.Err())
+		}
+	}
+	return v.add(&Rule{
+		reason:   denyReason,
+		ruleType: Custom,
+		bounded:  bounded,
+		function: func(input string) bool {
+			approved, _ := bounded(context.Background(), input)
+			return approved
+		},
+	})
+}
+
+func (v *Validator) NotEmpty() *Validator {
+	return v.add(&Rule{
+		ruleType: NotEmpty,
+		reason:   "must not be empty",
+		bytes: func(input []byte) bool {
+			return len(input) > 0
+		},
+		function: func(input string) bool {
+			return len(input) > 0
+		},
+	})
+}
+
+func (v *Validator) Required() *Validator {
+	return v.add(&Rule{
+		ruleType: Required,
+		reason:   "must not be blank",
+		function: func(input string) bool {
+			return strings.TrimSpace(input) != ""
+		},
+	})
+}
+
+func (v *Validator) StartsWith(text string) *Validator {
+	textBytes := []byte(text)
+	return v.add(&Rule{
+		ruleType: StartsWith,
+		reason:   fmt.Sprintf("starts with %s", text),
+		args:     []interface{}{text},
+		bytes: func(input []byte) bool {
+			return bytes.HasPrefix(input, textBytes)
+		},
+		function: func(input string) bool {
+			return strings.HasPrefix(input, text)
+		},
+	})
+}
+
+func (v *Validator) EndsWith(text string) *Validator {
+	textBytes := []byte(text)
+	return v.add(&Rule{
+		ruleType: EndsWith,
+		reason:   fmt.Sprintf("ends with %s", text),
+		args:     []interface{}{text},
+		bytes: func(input []byte) bool {
+			return bytes.HasSuffix(input, textBytes)
+		},
+		function: func(input string) bool {
+			return strings.HasSuffix(input, text)
+		},
+	})
+}
+
+func (v *Validator) StartsWithAny(prefixes []string) *Validator {
+	return v.add(&Rule{
+		ruleType: StartsWithAny,
+		reason:   fmt.Sprintf("starts with any of %v", prefixes),
+		args:     []interface{}{prefixes},
+		function: func(input string) bool {
+			for _, prefix := range prefixes {
+				if strings.HasPrefix(input, prefix) {
+					return true
+				}
+			}
+			return false
+		},
+	})
+}
+
+func (v *Validator) EndsWithAny(suffixes []string) *Validator {
+	return v.add(&Rule{
+		ruleType: EndsWithAny,
+		reason:   fmt.Sprintf("ends with any of %v", suffixes),
+		args:     []interface{}{suffixes},
+		function: func(input string) bool {
+			for _, suffix := range suffixes {
+				if strings.HasSuffix(input, suffix) {
+					return true
+				}
+			}
+			return false
+		},
+	})
+}
+
+// FoldLanguage makes the case-insensitive rules added after it fold case by
+// the rules of tag, e.g. mapping the Turkish dotted and dotless i correctly.
+// Without it they use the language independent Unicode folding.
+func (v *Validator) FoldLanguage(tag language.Tag) *Validator {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+	v.language = tag
+	return v
+}
+
+func (v *Validator) folder() (func(string) string, string) {
+	v.mutex.RLock()
+	tag := v.language
+	v.mutex.RUnlock()
+	if tag == language.Und {
+		return strings.ToLower, ""
+	}
+	return func(input string) string {
+		return cases.Lower(tag).String(input)
+	}, tag.String()
+}
+
+func (v *Validator) StartsWithFold(text string) *Validator {
+	lower, tag := v.folder()
+	folded := lower(text)
+	return v.add(&Rule{
+		ruleType: StartsWithFold,
+		reason:   fmt.Sprintf("starts with %s (case-insensitive)", text),
+		args:     []interface{}{text},
+		language: tag,
+		function: func(input string) bool {
+			return strings.HasPrefix(lower(input), folded)
+		},
+	})
+}
+
+func (v *Validator) EndsWithFold(text string) *Validator {
+	lower, tag := v.folder()
+	folded := lower(text)
+	return v.add(&Rule{
+		ruleType: EndsWithFold,
+		reason:   fmt.Sprintf("ends with %s (case-insensitive)", text),
+		args:     []interface{}{text},
+		language: tag,
+		function: func(input string) bool {
+			return strings.HasSuffix(lower(input), folded)
+		},
+	})
+}
+
+func (v *Validator) NotStartsWith(text string) *Validator {
+	textBytes := []byte(text)
+	return v.add(&Rule{
+		ruleType: NotStartsWith,
+		reason:   fmt.Sprintf("does not start with %s", text),
+		args:     []interface{}{text},
+		bytes: func(input []byte) bool {
+			return !bytes.HasPrefix(input, textBytes)
+		},
+		function: func(input string) bool {
+			return !strings.HasPrefix(input, text)
+		},
+	})
+}
+
+func (v *Validator) NotEndsWith(text string) *Validator {
+	textBytes := []byte(text)
+	return v.add(&Rule{
+		ruleType: NotEndsWith,
+		reason:   fmt.Sprintf("does not end with %s", text),
+		args:     []interface{}{text},
+		bytes: func(input []byte) bool {
+			return !bytes.HasSuffix(input, textBytes)
+		},
+		function: func(input string) bool {
+			return !strings.HasSuffix(input, text)
+		},
+	})
+}
+
+func (v *Validator) LongerThan(length int) *Validator {
+	return v.add(&Rule{
+		ruleType: LongerThan,
+		reason:   fmt.Sprintf("longer than %d", length),
+		args:     []interface{}{length},
+		function: func(input string) bool {
+			return utf8.RuneCountInString(input) > length
+		},
+	})
+}
+
+func (v *Validator) LongerThanOrEqual(length int) *Validator {
+	return v.add(&Rule{
+		ruleType: LongerThanOrEqual,
+		reason:   fmt.Sprintf("longer than or equal to %d", length),
+		args:     []interface{}{length},
+		function: func(input string) bool {
+			return utf8.RuneCountInString(input) >= length
+		},
+	})
+}
+
+func (v *Validator) ShorterThan(length int) *Validator {
+	return v.add(&Rule{
+		ruleType: ShorterThan,
+		reason:   fmt.Sprintf("shorter than %d", length),
+		args:     []interface{}{length},
+		function: func(input string) bool {
+			return utf8.RuneCountInString(input) < length
+		},
+	})
+}
+
+func (v *Validator) ShorterThanOrEqual(length int) *Validator {
+	return v.add(&Rule{
+		ruleType: ShorterThanOrEqual,
+		reason:   fmt.Sprintf("shorter than or equal to %d", length),
+		args:     []interface{}{length},
+		function: func(input string) bool {
+			return utf8.RuneCountInString(input) <= length
+		},
+	})
+}
+
+func (v *Validator) MinLength(length int) *Validator {
+	return v.add(&Rule{
+		ruleType: MinLength,
+		reason:   fmt.Sprintf("at least %d characters", length),
+		args:     []interface{}{length},
+		function: func(input string) bool {
+			return utf8.RuneCountInString(input) >= length
+		},
+	})
+}
+
+func (v *Validator) MaxLength(length int) *Validator {
+	return v.add(&Rule{
+		ruleType: MaxLength,
+		reason:   fmt.Sprintf("at most %d characters", length),
+		args:     []interface{}{length},
+		function: func(input string) bool {
+			return utf8.RuneCountInString(input) <= length
+		},
+	})
+}
+
+func (v *Validator) LengthBetween(min int, max int) *Validator {
+	return v.MinLength(min).MaxLength(max)
+}
+
+func (v *Validator) LengthEqual(length int) *Validator {
+	return v.add(&Rule{
+		ruleType: LengthEqual,
+		reason:   fmt.Sprintf("exactly %d characters", length),
+		args:     []interface{}{length},
+		function: func(input string) bool {
+			return utf8.RuneCountInString(input) == length
+		},
+	})
+}
+
+func (v *Validator) LengthDivisibleBy(n int) *Validator {
+	if n == 0 {
+		v.setErr(fmt.Errorf("length divisor must not be zero"))
+	}
+	return v.add(&Rule{
+		ruleType: LengthDivisibleBy,
+		reason:   fmt.Sprintf("length divisible by %d", n),
+		args:     []interface{}{n},
+		function: func(input string) bool {
+			return n != 0 && utf8.RuneCountInString(input)%n == 0
+		},
+	})
+}
+
+func (v *Validator) LongerThanBytes(length int) *Validator {
+	return v.add(&Rule{
+		ruleType: LongerThanBytes,
+		reason:   fmt.Sprintf("longer than %d bytes", length),
+		args:     []interface{}{length},
+		bytes: func(input []byte) bool {
+			return len(input) > length
+		},
+		function: func(input string) bool {
+			return len(input) > length
+		},
+	})
+}
+
+func (v *Validator) ShorterThanBytes(length int) *Validator {
+	return v.add(&Rule{
+		ruleType: ShorterThanBytes,
+		reason:   fmt.Sprintf("shorter than %d bytes", length),
+		args:     []interface{}{length},
+		bytes: func(input []byte) bool {
+			return len(input) < length
+		},
+		function: func(input string) bool {
+			return len(input) < length
+		},
+	})
+}
+
+func (v *Validator) Contains(text string) *Validator {
+	textBytes := []byte(text)
+	return v.add(&Rule{
+		ruleType: Contains,
+		reason:   fmt.Sprintf("contains %s", text),
+		args:     []interface{}{text},
+		bytes: func(input []byte) bool {
+			return bytes.Contains(input, textBytes)
+		},
+		function: func(input string) bool {
+			return strings.Contains(input, text)
+		},
+	})
+}
+
+func (v *Validator) ContainsFold(text string) *Validator {
+	lower, tag := v.folder()
+	folded := lower(text)
+	return v.add(&Rule{
+		ruleType: ContainsFold,
+		reason:   fmt.Sprintf("contains %s (case-insensitive)", text),
+		args:     []interface{}{text},
+		language: tag,
+		function: func(input string) bool {
+			return strings.Contains(lower(input), folded)
+		},
+	})
+}
+
+func (v *Validator) ContainsWord(word string) *Validator {
+	return v.add(&Rule{
+		ruleType: ContainsWord,
+		reason:   fmt.Sprintf("contains the word %s", word),
+		args:     []interface{}{word},
+		function: func(input string) bool {
+			return containsWord(input, word)
+		},
+	})
+}
+
+func (v *Validator) ContainsAny(texts []string) *Validator {
+	return v.add(&Rule{
+		ruleType: ContainsAny,
+		reason:   fmt.Sprintf("contains any of %v", texts),
+		args:     []interface{}{texts},
+		function: func(input string) bool {
+			for _, text := range texts {
+				if strings.Contains(input, text) {
+					return true
+				}
+			}
+			return false
+		},
+	})
+}
+
+func (v *Validator) ContainsAll(texts []string) *Validator {
+	return v.add(&Rule{
+		ruleType: ContainsAll,
+		reason:   fmt.Sprintf("contains all of %v", texts),
+		args:     []interface{}{texts},
+		function: func(input string) bool {
+			for _, text := range texts {
+				if !strings.Contains(input, text) {
+					return false
+				}
+			}
+			return true
+		},
+	})
+}
+
+func (v *Validator) ContainsAtLeast(text string, n int) *Validator {
+	return v.add(&Rule{
+		ruleType: ContainsAtLeast,
+		reason:   fmt.Sprintf("contains %s at least %d times", text, n),
+		args:     []interface{}{text, n},
+		function: func(input string) bool {
+			return strings.Count(input, text) >= n
+		},
+	})
+}
+
+func (v *Validator) ContainsExactly(text string, n int) *Validator {
+	return v.add(&Rule{
+		ruleType: ContainsExactly,
+		reason:   fmt.Sprintf("contains %s exactly %d times", text, n),
+		args:     []interface{}{text, n},
+		function: func(input string) bool {
+			return strings.Count(input, text) == n
+		},
+	})
+}
+
+func (v *Validator) NotContains(text string) *Validator {
+	textBytes := []byte(text)
+	return v.add(&Rule{
+		ruleType: NotContains,
+		reason:   fmt.Sprintf("does not contain %s", text),
+		args:     []interface{}{text},
+		bytes: func(input []byte) bool {
+			return !bytes.Contains(input, textBytes)
+		},
+		function: func(input string) bool {
+			return !strings.Contains(input, text)
+		},
+	})
+}
+
+func (v *Validator) NotContainsAny(texts []string) *Validator {
+	reason := fmt.Sprintf("does not contain any of %v", texts)
+	return v.blockList(NotContainsAny, reason, texts, identity, "")
+}
+
+func (v *Validator) BlockList(words []string) *Validator {
+	reason := fmt.Sprintf("contains none of %d blocked words", len(words))
+	return v.blockList(BlockList, reason, words, identity, "")
+}
+
+func (v *Validator) BlockListFold(words []string) *Validator {
+	lower, tag := v.folder()
+	reason := fmt.Sprintf("contains none of %d blocked words (case-insensitive)", len(words))
+	return v.blockList(BlockListFold, reason, words, lower, tag)
+}
+
+func identity(input string) string {
+	return input
+}
+
+// BlockListFromFile reads one blocked word per line from path, skipping blank
+// lines and lines starting with #, and adds a BlockList or, when ignoreCase is
+// set, a BlockListFold rule with them.
+func (v *Validator) BlockListFromFile(path string, ignoreCase bool) (*Validator, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return v, err
+	}
+	words := []string{}
+	for _, line := range strings.Split(string(data), "\n") {
+		word := strings.TrimSpace(line)
+		if word != "" && !strings.HasPrefix(word, "#") {
+			words = append(words, word)
+		}
+	}
+	if ignoreCase {
+		return v.BlockListFold(words), nil
+	}
+	return v.BlockList(words), nil
+}
+
+func (v *Validator) blockList(ruleType RuleType, reason string, words []string, fold func(string) string, tag string) *Validator {
+	folded := make([]string, len(words))
+	for i, word := range words {
+		folded[i] = fold(word)
+	}
+	m := newMatcher(folded)
+	blocked := func(input string) (string, bool) {
+		i, found := m.find(fold(input))
+		if !found {
+			return "", false
+		}
+		return words[i], true
+	}
+	return v.add(&Rule{
+		ruleType: ruleType,
+		reason:   reason,
+		args:     []interface{}{words},
+		language: tag,
+		function: func(input string) bool {
+			_, found := blocked(input)
+			return !found
+		},
+		detail: func(input string) string {
+			word, _ := blocked(input)
+			return fmt.Sprintf("contains %q", word)
+		},
+	})
+}
+
+func (v *Validator) ContainsACharacter() *Validator {
+	return v.add(&Rule{
+		ruleType: ContainsACharacter,
+		reason:   "contains a character",
+		function: func(input string) bool {
+			for _, r := range input {
+				if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') {
+					return true
+				}
+			}
+			return false
+		},
+	})
+}
+
+func (v *Validator) ContainsANumber() *Validator {
+	return v.add(&Rule{
+		ruleType: ContainsANumber,
+		reason:   "contains a number",
+		function: func(input string) bool {
+			for _, r := range input {
+				if r >= '0' && r <= '9' {
+					return true
+				}
+			}
+			return false
+		},
+	})
+}
+
+func (v *Validator) IsNumeric() *Validator {
+	return v.add(&Rule{
+		ruleType: IsNumeric,
+		reason:   "is numeric",
+		function: func(input string) bool {
+			if input == "" {
+				return false
+			}
+			for _, r := range input {
+				if r < '0' || r > '9' {
+					return false
+				}
+			}
+			return true
+		},
+	})
+}
+
+func (v *Validator) NumericRange(min int, max int) *Validator {
+	return v.add(&Rule{
+		ruleType: NumericRange,
+		reason:   fmt.Sprintf("integer between %d and %d", min, max),
+		args:     []interface{}{min, max},
+		function: func(input string) bool {
+			n, err := strconv.Atoi(input)
+			if err != nil {
+				return false
+			}
+			return n >= min && n <= max
+		},
+	})
+}
+
+func (v *Validator) ValueDivisibleBy(n int) *Validator {
+	if n == 0 {
+		v.setErr(fmt.Errorf("value divisor must not be zero"))
+	}
+	return v.add(&Rule{
+		ruleType: ValueDivisibleBy,
+		reason:   fmt.Sprintf("integer divisible by %d", n),
+		args:     []interface{}{n},
+		function: func(input string) bool {
+			value, err := strconv.Atoi(input)
+			return err == nil && n != 0 && value%n == 0
+		},
+	})
+}
+
+func (v *Validator) IsAlphanumeric() *Validator {
+	return v.add(&Rule{
+		ruleType: IsAlphanumeric,
+		reason:   "is alphanumeric (unicode letters and digits)",
+		function: func(input string) bool {
+			if input == "" {
+				return false
+			}
+			for _, r := range input {
+				if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+					return false
+				}
+			}
+			return true
+		},
+	})
+}
+
+func (v *Validator) IsUppercase() *Validator {
+	return v.add(&Rule{
+		ruleType: IsUppercase,
+		reason:   "is uppercase",
+		function: func(input string) bool {
+			return input == strings.ToUpper(input) && strings.IndexFunc(input, unicode.IsUpper) >= 0
+		},
+	})
+}
+
+func (v *Validator) IsLowercase() *Validator {
+	return v.add(&Rule{
+		ruleType: IsLowercase,
+		reason:   "is lowercase",
+		function: func(input string) bool {
+			return input == strings.ToLower(input) && strings.IndexFunc(input, unicode.IsLower) >= 0
+		},
+	})
+}
+
+func (v *Validator) NoWhitespace() *Validator {
+	return v.add(&Rule{
+		ruleType: NoWhitespace,
+		reason:   "contains no whitespace",
+		function: func(input string) bool {
+			return strings.IndexFunc(input, unicode.IsSpace) < 0
+		},
+	})
+}
+
+func (v *Validator) Trimmed() *Validator {
+	return v.add(&Rule{
+		ruleType: Trimmed,
+		reason:   "has no leading or trailing whitespace",
+		function: func(input string) bool {
+			return strings.TrimSpace(input) == input
+		},
+	})
+}
+
+func (v *Validator) OnlyScript(name string) *Validator {
+	script, found := unicode.Scripts[name]
+	if !found {
+		v.setErr(fmt.Errorf("unknown unicode script %s", name))
+	}
+	reason := fmt.Sprintf("letters only from %s script", name)
+	offending := func(input string) (rune, bool) {
+		for _, r := range input {
+			if unicode.IsLetter(r) && (!found || !unicode.Is(script, r)) {
+				return r, true
+			}
+		}
+		return 0, false
+	}
+	return v.add(&Rule{
+		ruleType: OnlyScript,
+		reason:   reason,
+		args:     []interface{}{name},
+		function: func(input string) bool {
+			_, denied := offending(input)
+			return !denied
+		},
+		detail: func(input string) string {
+			r, _ := offending(input)
+			return fmt.Sprintf("%q is %s", r, scriptOf(r))
+		},
+	})
+}
+
+func (v *Validator) NoControlCharacters() *Validator {
+	reason := "contains no control characters"
+	return v.add(&Rule{
+		ruleType: NoControlCharacters,
+		reason:   reason,
+		function: func(input string) bool {
+			return strings.IndexFunc(input, unicode.IsControl) < 0
+		},
+		dynamic: func(input string) (bool, string) {
+			i := strings.IndexFunc(input, unicode.IsControl)
+			if i < 0 {
+				return true, ""
+			}
+			r, _ := utf8.DecodeRuneInString(input[i:])
+			return false, fmt.Sprintf("%s (%U is a control character)", notMet(reason, input), r)
+		},
+	})
+}
+
+func (v *Validator) IsPrintableASCII() *Validator {
+	return v.add(&Rule{
+		ruleType: IsPrintableASCII,
+		reason:   "printable ascii only",
+		function: func(input string) bool {
+			for i := 0; i < len(input); i++ {
+				if input[i] < 0x20 || input[i] > 0x7e {
+					return false
+				}
+			}
+			return true
+		},
+		bytes: func(input []byte) bool {
+			for _, b := range input {
+				if b < 0x20 || b > 0x7e {
+					return false
+				}
+			}
+			return true
+		},
+	})
+}
+
+func (v *Validator) RunesInRange(low rune, high rune) *Validator {
+	return v.add(&Rule{
+		ruleType: RunesInRange,
+		reason:   fmt.Sprintf("runes in range %U to %U", low, high),
+		args:     []interface{}{low, high},
+		function: func(input string) bool {
+			for _, r := range input {
+				if r < low || r > high {
+					return false
+				}
+			}
+			return true
+		},
+	})
+}
+
+func (v *Validator) Not(build func(*Validator) *Validator) *Validator {
+	return v.not(build(NewValidator()))
+}
+
+func (v *Validator) not(inner *Validator) *Validator {
+	if err := inner.Err(); err != nil {
+		v.setErr(err)
+	}
+	return v.add(&Rule{
+		ruleType: Not,
+		reason:   fmt.Sprintf("not %s", strings.Join(inner.reasons(), " and ")),
+		args:     []interface{}{inner},
+		function: func(input string) bool {
+			return !inner.passes(input)
+		},
+	})
+}
+
+func (v *Validator) Any(validators ...*Validator) *Validator {
+	branches := make([]string, len(validators))
+	args := make([]interface{}, len(validators))
+	for i, validator := range validators {
+		branches[i] = strings.Join(validator.reasons(), " and ")
+		args[i] = validator
+		if err := validator.Err(); err != nil {
+			v.setErr(err)
+		}
+	}
+	return v.add(&Rule{
+		ruleType: Any,
+		reason:   strings.Join(branches, " or "),
+		args:     args,
+		function: func(input string) bool {
+			for _, validator := range validators {
+				if validator.passes(input) {
+					return true
+				}
+			}
+			return false
+		},
+	})
+}
+
+func (v *Validator) Or(builds ...func(*Validator) *Validator) *Validator {
+	validators := make([]*Validator, len(builds))
+	for i, build := range builds {
+		validators[i] = build(NewValidator())
+	}
+	return v.or(validators)
+}
+
+func (v *Validator) or(validators []*Validator) *Validator {
+	branches := make([]string, len(validators))
+	args := make([]interface{}, len(validators))
+	for i, validator := range validators {
+		branches[i] = strings.Join(validator.reasons(), " and ")
+		args[i] = validator
+		if err := validator.Err(); err != nil {
+			v.setErr(err)
+		}
+	}
+	return v.add(&Rule{
+		ruleType: Or,
+		reason:   strings.Join(branches, " or "),
+		args:     args,
+		function: func(input string) bool {
+			for _, validator := range validators {
+				if validator.passes(input) {
+					return true
+				}
+			}
+			return false
+		},
+	})
+}
+
+func (v *Validator) When(condition func(input string) bool, build func(*Validator) *Validator) *Validator {
+	inner := build(NewValidator())
+	if err := inner.Err(); err != nil {
+		v.setErr(err)
+	}
+	return v.add(&Rule{
+		ruleType: When,
+		reason:   fmt.Sprintf("when condition holds %s", strings.Join(inner.reasons(), " and ")),
+		function: func(input string) bool {
+			return !condition(input) || inner.passes(input)
+		},
+	})
+}
+
+func (v *Validator) EqualTo(text string) *Validator {
+	return v.add(&Rule{
+		ruleType: EqualTo,
+		reason:   fmt.Sprintf("equal to %s", text),
+		args:     []interface{}{text},
+		bytes: func(input []byte) bool {
+			return string(input) == text
+		},
+		function: func(input string) bool {
+			return input == text
+		},
+	})
+}
+
+func (v *Validator) NotEqualTo(text string) *Validator {
+	return v.add(&Rule{
+		ruleType: NotEqualTo,
+		reason:   fmt.Sprintf("not equal to %s", text),
+		args:     []interface{}{text},
+		bytes: func(input []byte) bool {
+			return string(input) != text
+		},
+		function: func(input string) bool {
+			return input != text
+		},
+	})
+}
+
+func (v *Validator) LexBetween(low string, high string) *Validator {
+	return v.add(&Rule{
+		ruleType: LexBetween,
+		reason:   fmt.Sprintf("between %s and %s inclusive", low, high),
+		args:     []interface{}{low, high},
+		function: func(input string) bool {
+			return low <= input && input <= high
+		},
+	})
+}
+
+func (v *Validator) LexBetweenExclusive(low string, high string) *Validator {
+	return v.add(&Rule{
+		ruleType: LexBetweenExclusive,
+		reason:   fmt.Sprintf("between %s and %s exclusive", low, high),
+		args:     []interface{}{low, high},
+		function: func(input string) bool {
+			return low < input && input < high
+		},
+	})
+}
+
+func (v *Validator) Ignore(text string) *Validator {
+	return v.add(&Rule{
+		ruleType: Ignore,
+		reason:   fmt.Sprintf("ignore %s", text),
+		args:     []interface{}{text},
+		bytes: func(input []byte) bool {
+			return string(input) != text
+		},
+		function: func(input string) bool {
+			return input != text
+		},
+	})
+}
+
+func (v *Validator) IgnoreFold(text string) *Validator {
+	lower, tag := v.folder()
+	folded := lower(text)
+	return v.add(&Rule{
+		ruleType: IgnoreFold,
+		reason:   fmt.Sprintf("ignore %s (case-insensitive)", text),
+		args:     []interface{}{text},
+		language: tag,
+		function: func(input string) bool {
+			if tag == "" {
+				return !strings.EqualFold(input, text)
+			}
+			return lower(input) != folded
+		},
+	})
+}
+
+func (v *Validator) IgnoreAll(texts []string) *Validator {
+	for _, text := range texts {
+		v.Ignore(text)
+	}
+	return v
+}
+
+func (v *Validator) OneOf(allowed []string) *Validator {
+	set := make(map[string]struct{}, len(allowed))
+	for _, text := range allowed {
+		set[text] = struct{}{}
+	}
+	return v.add(&Rule{
+		ruleType: OneOf,
+		reason:   fmt.Sprintf("one of %v", allowed),
+		args:     []interface{}{allowed},
+		function: func(input string) bool {
+			_, found := set[input]
+			return found
+		},
+	})
+}
+
+func (v *Validator) OneOfStringers(values ...fmt.Stringer) *Validator {
+	allowed := make([]string, len(values))
+	for i, value := range values {
+		allowed[i] = value.String()
+	}
+	return v.OneOf(allowed)
+}
+
+func (v *Validator) OneOfFold(allowed []string) *Validator {
+	lower, tag := v.folder()
+	set := make(map[string]struct{}, len(allowed))
+	for _, text := range allowed {
+		set[lower(text)] = struct{}{}
+	}
+	return v.add(&Rule{
+		ruleType: OneOfFold,
+		reason:   fmt.Sprintf("one of %v (case-insensitive)", allowed),
+		args:     []interface{}{allowed},
+		language: tag,
+		function: func(input string) bool {
+			_, found := set[lower(input)]
+			return found
+		},
+	})
+}
+
+func (v *Validator) Regexp(r string) *Validator {
+	re, err := regexp.Compile(r)
+	if err != nil {
+		v.setErr(fmt.Errorf("regexp %s: %w", r, err))
+	}
+	return v.add(&Rule{
+		ruleType: Regexp,
+		reason:   fmt.Sprintf("regexp %s", r),
+		args:     []interface{}{r},
+		function: func(input string) bool {
+			if err != nil {
+				return false
+			}
+			return re.MatchString(input)
+		},
+		bytes: func(input []byte) bool {
+			if err != nil {
+				return false
+			}
+			return re.Match(input)
+		},
+	})
+}
+
+func (v *Validator) Matches(re *regexp.Regexp) *Validator {
+	return v.add(&Rule{
+		ruleType: Regexp,
+		reason:   fmt.Sprintf("regexp %s", re.String()),
+		args:     []interface{}{re.String()},
+		function: re.MatchString,
+		bytes:    re.Match,
+	})
+}
+
+func (v *Validator) MatchesOneOf(patterns []string) *Validator {
+	compiled := make([]*regexp.Regexp, 0, len(patterns))
+	for _, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			v.setErr(fmt.Errorf("regexp %s: %w", pattern, err))
+			continue
+		}
+		compiled = append(compiled, re)
+	}
+	return v.add(&Rule{
+		ruleType: MatchesOneOf,
+		reason:   fmt.Sprintf("matches one of %v", patterns),
+		args:     []interface{}{patterns},
+		function: func(input string) bool {
+			for _, re := range compiled {
+				if re.MatchString(input) {
+					return true
+				}
+			}
+			return false
+		},
+		bytes: func(input []byte) bool {
+			for _, re := range compiled {
+				if re.Match(input) {
+					return true
+				}
+			}
+			return false
+		},
+	})
+}
+
+func (v *Validator) Email() *Validator {
+	return v.add(&Rule{
+		ruleType: Email,
+		reason:   "valid email address",
+		function: isEmail,
+	})
+}
+
+func (v *Validator) EmailStrict() *Validator {
+	return v.add(&Rule{
+		ruleType: EmailStrict,
+		reason:   "valid email address (strict, no display name)",
+		function: func(input string) bool {
+			address, err := mail.ParseAddress(input)
+			return err == nil && address.Name == "" && address.Address == input
+		},
+	})
+}
+
+func (v *Validator) EmailWithName() *Validator {
+	return v.add(&Rule{
+		ruleType: EmailWithName,
+		reason:   "valid email address (display name allowed)",
+		function: func(input string) bool {
+			_, err := mail.ParseAddress(input)
+			return err == nil
+		},
+	})
+}
+
+func (v *Validator) URL(schemes ...string) *Validator {
+	reason := "valid URL"
+	if len(schemes) > 0 {
+		reason = fmt.Sprintf("valid URL with scheme %s", strings.Join(schemes, " or "))
+	}
+	return v.add(&Rule{
+		ruleType: URL,
+		reason:   reason,
+		args:     []interface{}{schemes},
+		function: func(input string) bool {
+			u, err := url.Parse(input)
+			if err != nil || !u.IsAbs() || u.Host == "" {
+				return false
+			}
+			if len(schemes) == 0 {
+				return true
+			}
+			for _, scheme := range schemes {
+				if strings.EqualFold(u.Scheme, scheme) {
+					return true
+				}
+			}
+			return false
+		},
+	})
+}
+
+func (v *Validator) IsHostname() *Validator {
+	return v.add(&Rule{
+		ruleType: IsHostname,
+		reason:   "valid hostname",
+		function: func(input string) bool {
+			_, ok := hostnameLabels(input)
+			return ok
+		},
+	})
+}
+
+func (v *Validator) IsFQDN() *Validator {
+	return v.add(&Rule{
+		ruleType: IsFQDN,
+		reason:   "fully qualified domain name",
+		function: func(input string) bool {
+			labels, ok := hostnameLabels(input)
+			return ok && (labels > 1 || strings.HasSuffix(input, "."))
+		},
+	})
+}
+
+func (v *Validator) IsIP() *Validator {
+	return v.add(&Rule{
+		ruleType: IsIP,
+		reason:   "is a valid IP address",
+		function: func(input string) bool {
+			return net.ParseIP(input) != nil
+		},
+	})
+}
+
+func (v *Validator) IsIPv4() *Validator {
+	return v.add(&Rule{
+		ruleType: IsIPv4,
+		reason:   "is a valid IPv4 address",
+		function: func(input string) bool {
+			ip := net.ParseIP(input)
+			return ip != nil && ip.To4() != nil && !strings.Contains(input, ":")
+		},
+	})
+}
+
+func (v *Validator) IsIPv6() *Validator {
+	return v.add(&Rule{
+		ruleType: IsIPv6,
+		reason:   "is a valid IPv6 address",
+		function: func(input string) bool {
+			return net.ParseIP(input) != nil && strings.Contains(input, ":")
+		},
+	})
+}
+
+func (v *Validator) IsMAC() *Validator {
+	return v.add(&Rule{
+		ruleType: IsMAC,
+		reason:   "valid mac address",
+		function: func(input string) bool {
+			_, ok := parseMAC(input)
+			return ok
+		},
+	})
+}
+
+func (v *Validator) Date(layout string) *Validator {
+	return v.add(&Rule{
+		ruleType: Date,
+		reason:   fmt.Sprintf("matches date layout %s", layout),
+		args:     []interface{}{layout},
+		function: func(input string) bool {
+			_, err := time.Parse(layout, input)
+			return err == nil
+		},
+	})
+}
+
+func (v *Validator) DateBetween(layout string, from time.Time, to time.Time) *Validator {
+	return v.add(&Rule{
+		ruleType: DateBetween,
+		reason:   fmt.Sprintf("date with layout %s between %s and %s", layout, from.Format(layout), to.Format(layout)),
+		args:     []interface{}{layout, from, to},
+		function: func(input string) bool {
+			date, err := time.Parse(layout, input)
+			if err != nil {
+				return false
+			}
+			return !date.Before(from) && !date.After(to)
+		},
+	})
+}
+
+func (v *Validator) IsDuration() *Validator {
+	return v.add(&Rule{
+		ruleType: IsDuration,
+		reason:   "valid duration",
+		function: func(input string) bool {
+			_, err := time.ParseDuration(input)
+			return err == nil
+		},
+	})
+}
+
+func (v *Validator) DurationBetween(min time.Duration, max time.Duration) *Validator {
+	return v.add(&Rule{
+		ruleType: DurationBetween,
+		reason:   fmt.Sprintf("duration between %s and %s", min, max),
+		args:     []interface{}{min, max},
+		function: func(input string) bool {
+			duration, err := time.ParseDuration(input)
+			if err != nil {
+				return false
+			}
+			return duration >= min && duration <= max
+		},
+	})
+}
+
+func (v *Validator) IsJSON() *Validator {
+	return v.add(&Rule{
+		ruleType: IsJSON,
+		reason:   "is valid JSON",
+		function: func(input string) bool {
+			return json.Valid([]byte(input))
+		},
+	})
+}
+
+func (v *Validator) IsHex() *Validator {
+	return v.add(&Rule{
+		ruleType: IsHex,
+		reason:   "is hexadecimal",
+		function: func(input string) bool {
+			if input == "" {
+				return false
 			}
-		}
-		v.mutex.Lock()
-		v.recents[input] = time.Now().Add(v.ignoreDuration).UnixNano()
-		v.mutex.Unlock()
-	}
-	return &Result{
-		Approval: true,
-	}
+			for _, r := range input {
+				if !((r >= '0' && r <= '9') || (r >= 'a' && r <= 'f') || (r >= 'A' && r <= 'F')) {
+					return false
+				}
+			}
+			return true
+		},
+	})
 }
 
-func (v *Validator) Custom(denyReason string, function func(input string) bool) *Validator {
-	v.rules = append(v.rules, &Rule{
-		reason:   denyReason,
-		ruleType: Custom,
-		function: function,
+func (v *Validator) IsHexBytes() *Validator {
+	return v.add(&Rule{
+		ruleType: IsHexBytes,
+		reason:   "is hex encoded bytes",
+		function: func(input string) bool {
+			_, err := hex.DecodeString(input)
+			return input != "" && err == nil
+		},
 	})
-	return v
 }
 
-func (v *Validator) StartsWith(text string) *Validator {
-	v.rules = append(v.rules, &Rule{
-		ruleType: StartsWith,
-		reason:   fmt.Sprintf("starts with %s", text),
+func (v *Validator) IsBase64() *Validator {
+	return v.add(&Rule{
+		ruleType: IsBase64,
+		reason:   "is base64 encoded",
 		function: func(input string) bool {
-			return strings.HasPrefix(input, text)
+			_, err := base64.StdEncoding.DecodeString(input)
+			return input != "" && err == nil
 		},
 	})
-	return v
 }
 
-func (v *Validator) EndsWith(text string) *Validator {
-	v.rules = append(v.rules, &Rule{
-		ruleType: EndsWith,
-		reason:   fmt.Sprintf("ends with %s", text),
+func (v *Validator) IsBase64URL() *Validator {
+	return v.add(&Rule{
+		ruleType: IsBase64URL,
+		reason:   "is URL-safe base64 encoded",
 		function: func(input string) bool {
-			return strings.HasSuffix(input, text)
+			_, err := base64.URLEncoding.DecodeString(input)
+			return input != "" && err == nil
 		},
 	})
-	return v
 }
 
-func (v *Validator) LongerThan(length int) *Validator {
-	v.rules = append(v.rules, &Rule{
-		ruleType: LongerThan,
-		reason:   fmt.Sprintf("longer than %d", length),
+func (v *Validator) IsUUID() *Validator {
+	return v.add(&Rule{
+		ruleType: IsUUID,
+		reason:   "is a UUID",
+		function: isUUID,
+	})
+}
+
+func (v *Validator) IsUUIDVersion(version int) *Validator {
+	return v.add(&Rule{
+		ruleType: IsUUIDVersion,
+		reason:   fmt.Sprintf("is a version %d UUID", version),
+		args:     []interface{}{version},
 		function: func(input string) bool {
-			return len(input) > length
+			if !isUUID(input) {
+				return false
+			}
+			n, _ := strconv.ParseUint(input[14:15], 16, 8)
+			return int(n) == version && strings.ContainsRune("89abAB", rune(input[19]))
 		},
 	})
-	return v
 }
 
-func (v *Validator) LongerThanOrEqual(length int) *Validator {
-	v.rules = append(v.rules, &Rule{
-		ruleType: LongerThanOrEqual,
-		reason:   fmt.Sprintf("longer than or equal to %d", length),
+func (v *Validator) IsCreditCard() *Validator {
+	return v.add(&Rule{
+		ruleType: IsCreditCard,
+		reason:   "passes Luhn check",
 		function: func(input string) bool {
-			return len(input) >= length
+			_, ok := creditCardNumber(input)
+			return ok
 		},
 	})
-	return v
 }
 
-func (v *Validator) ShorterThan(length int) *Validator {
-	v.rules = append(v.rules, &Rule{
-		ruleType: ShorterThan,
-		reason:   fmt.Sprintf("shorter than %d", length),
+func (v *Validator) IsCreditCardBrand(brand string) *Validator {
+	matches, found := creditCardBrands[strings.ToLower(brand)]
+	if !found {
+		v.setErr(fmt.Errorf("unknown credit card brand %s", brand))
+	}
+	return v.add(&Rule{
+		ruleType: IsCreditCardBrand,
+		reason:   fmt.Sprintf("%s card number passing Luhn check", brand),
+		args:     []interface{}{brand},
 		function: func(input string) bool {
-			return len(input) < length
+			number, ok := creditCardNumber(input)
+			return ok && found && matches(number)
 		},
 	})
-	return v
 }
 
-func (v *Validator) ShorterThanOrEqual(length int) *Validator {
-	v.rules = append(v.rules, &Rule{
-		ruleType: ShorterThanOrEqual,
-		reason:   fmt.Sprintf("shorter than or equal to %d", length),
+func (v *Validator) IsSlug() *Validator {
+	return v.add(&Rule{
+		ruleType: IsSlug,
+		reason:   "is a slug",
 		function: func(input string) bool {
-			return len(input) <= length
+			return isSlug(input, "-")
 		},
 	})
-	return v
 }
 
-func (v *Validator) Contains(text string) *Validator {
-	v.rules = append(v.rules, &Rule{
-		ruleType: Contains,
-		reason:   fmt.Sprintf("contains %s", text),
+func (v *Validator) IsSlugUnderscore() *Validator {
+	return v.add(&Rule{
+		ruleType: IsSlugUnderscore,
+		reason:   "is a slug (underscores allowed)",
 		function: func(input string) bool {
-			return strings.Contains(input, text)
+			return isSlug(input, "-_")
 		},
 	})
-	return v
 }
 
-func (v *Validator) ContainsACharacter() *Validator {
-	v.rules = append(v.rules, &Rule{
-		ruleType: ContainsACharacter,
-		reason:   "contains a character",
+func (v *Validator) IsGoIdentifier() *Validator {
+	return v.add(&Rule{
+		ruleType: IsGoIdentifier,
+		reason:   "valid go identifier",
+		function: token.IsIdentifier,
+	})
+}
+
+func (v *Validator) IsPhone(region string) *Validator {
+	region = strings.ToUpper(region)
+	pattern, found := phonePatterns[region]
+	if !found {
+		v.setErr(fmt.Errorf("unknown phone region %s", region))
+	}
+	reason := "is an E.164 phone number"
+	if region != "" {
+		reason = fmt.Sprintf("is a %s phone number", region)
+	}
+	return v.add(&Rule{
+		ruleType: IsPhone,
+		reason:   reason,
+		args:     []interface{}{region},
 		function: func(input string) bool {
-			for _, r := range input {
-				if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') {
-					return true
-				}
+			if !found {
+				return false
 			}
-			return false
+			if region != "" {
+				input = phoneSeparators.Replace(input)
+			}
+			return pattern.MatchString(input)
 		},
 	})
-	return v
 }
 
-func (v *Validator) ContainsANumber() *Validator {
-	v.rules = append(v.rules, &Rule{
-		ruleType: ContainsANumber,
-		reason:   "contains a number",
+func (v *Validator) IsSemVer() *Validator {
+	return v.add(&Rule{
+		ruleType: IsSemVer,
+		reason:   "valid semantic version",
 		function: func(input string) bool {
-			for _, r := range input {
-				if r >= '0' && r <= '9' {
-					return true
-				}
-			}
-			return false
+			_, ok := parseSemVer(input)
+			return ok
 		},
 	})
-	return v
 }
 
-func (v *Validator) Ignore(text string) *Validator {
-	v.rules = append(v.rules, &Rule{
-		ruleType: Ignore,
-		reason:   fmt.Sprintf("ignore %s", text),
+func (v *Validator) SemVerAtLeast(version string) *Validator {
+	least, ok := parseSemVer(version)
+	if !ok {
+		v.setErr(fmt.Errorf("invalid semantic version %s", version))
+	}
+	return v.add(&Rule{
+		ruleType: SemVerAtLeast,
+		reason:   fmt.Sprintf("semantic version at least %s", version),
+		args:     []interface{}{version},
 		function: func(input string) bool {
-			return input != text
+			parsed, valid := parseSemVer(input)
+			return ok && valid && compareSemVer(parsed, least) >= 0
 		},
 	})
-	return v
 }
 
-func (v *Validator) IgnoreAll(texts []string) *Validator {
-	for _, text := range texts {
-		v.Ignore(text)
+type PasswordOptions struct {
+	MinLength int
+	Upper     bool
+	Lower     bool
+	Digit     bool
+	Symbol    bool
+}
+
+func (v *Validator) PasswordStrength(opts PasswordOptions) *Validator {
+	if opts.MinLength > 0 {
+		v.passwordRule("password-length", fmt.Sprintf("at least %d characters", opts.MinLength), func(input string) bool {
+			return utf8.RuneCountInString(input) >= opts.MinLength
+		})
+	}
+	if opts.Upper {
+		v.passwordRule("password-upper", "contains an uppercase letter", func(input string) bool {
+			return strings.IndexFunc(input, unicode.IsUpper) >= 0
+		})
+	}
+	if opts.Lower {
+		v.passwordRule("password-lower", "contains a lowercase letter", func(input string) bool {
+			return strings.IndexFunc(input, unicode.IsLower) >= 0
+		})
+	}
+	if opts.Digit {
+		v.passwordRule("password-digit", "contains a digit", func(input string) bool {
+			return strings.IndexFunc(input, unicode.IsDigit) >= 0
+		})
+	}
+	if opts.Symbol {
+		v.passwordRule("password-symbol", "contains a symbol", func(input string) bool {
+			return strings.IndexFunc(input, func(r rune) bool {
+				return unicode.IsPunct(r) || unicode.IsSymbol(r)
+			}) >= 0
+		})
 	}
 	return v
 }
 
-func (v *Validator) Regexp(r string) *Validator {
-	v.rules = append(v.rules, &Rule{
-		ruleType: Regexp,
-		reason:   fmt.Sprintf("regexp %s", r),
-		function: func(input string) bool {
-			matched, err := regexp.MatchString(r, input)
-			if err != nil {
-				return false
-			}
-			return matched
-		},
+func (v *Validator) passwordRule(label string, reason string, function func(input string) bool) {
+	v.add(&Rule{
+		ruleType: PasswordStrength,
+		reason:   reason,
+		label:    label,
+		function: function,
 	})
+}
+
+func (v *Validator) WithClock(now func() time.Time) *Validator {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+	v.now = now
 	return v
 }
 
 func (v *Validator) IgnoreDuplicatesFor(duration time.Duration) *Validator {
+	if duration <= 0 {
+		return v.StopIgnoringDuplicates()
+	}
+	v.mutex.Lock()
+	if v.cleaning {
+		close(v.close)
+		v.close = make(chan struct{})
+	}
+	done := v.close
+	v.cleaning = true
+	v.ignoreDuration = duration
+	v.mutex.Unlock()
+
+	interval := duration / 2
+	if interval <= 0 {
+		interval = duration
+	}
+
 	go func() {
-		ticker := time.NewTicker(duration / 2)
+		ticker := time.NewTicker(interval)
 		defer ticker.Stop()
 
 		for {
 			select {
 			case <-ticker.C:
 				v.mutex.Lock()
-				for text, expires := range v.recents {
-					if time.Now().UnixNano() > expires {
-						delete(v.recents, text)
-					}
-				}
+				v.expireRecents(v.now().UnixNano())
 				v.mutex.Unlock()
 
-			case <-v.close:
+			case <-done:
 				return
 			}
 		}
 	}()
-	v.ignoreDuration = duration
 	return v
 }
 
+func (v *Validator) IgnoreDuplicatesForWithLimit(duration time.Duration, maxEntries int) *Validator {
+	v.mutex.Lock()
+	v.maxRecents = maxEntries
+	v.mutex.Unlock()
+	return v.IgnoreDuplicatesFor(duration)
+}
+
 func (v *Validator) StopIgnoringDuplicates() *Validator {
-	v.ignoreDuration = 0
-	v.close <- struct{}{}
 	v.mutex.Lock()
+	defer v.mutex.Unlock()
+	v.ignoreDuration = 0
+	if v.cleaning {
+		close(v.close)
+		v.close = make(chan struct{})
+		v.cleaning = false
+	}
 	v.recents = make(map[string]int64)
-	v.mutex.Unlock()
+	v.expiries = nil
+	return v
+}
+
+func (v *Validator) Reset() *Validator {
+	v.StopIgnoringDuplicates()
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+	v.rules = []*Rule{}
+	v.maxRecents = 0
+	v.denyByDefault = false
+	v.requireRules = false
+	v.redact = false
+	v.stats = nil
+	v.aggregate = false
+	v.preprocess = nil
+	v.onEvaluate = nil
+	v.language = language.Tag{}
+	v.err = nil
+	v.now = time.Now
+	return v
+}
+
+func (v *Validator) Clone() *Validator {
+	clone := NewValidator()
+	for _, r := range v.snapshot() {
+		copied := *r
+		clone.rules = append(clone.rules, &copied)
+	}
+	clone.err = v.Err()
+	v.mutex.RLock()
+	clone.now = v.now
+	clone.maxRecents = v.maxRecents
+	clone.denyByDefault = v.denyByDefault
+	clone.requireRules = v.requireRules
+	clone.redact = v.redact
+	clone.aggregate = v.aggregate
+	clone.preprocess = v.preprocess
+	clone.onEvaluate = v.onEvaluate
+	clone.language = v.language
+	if v.stats != nil {
+		clone.stats = map[RuleType]int64{}
+	}
+	v.mutex.RUnlock()
+	return clone
+}
+
+// Merge appends the rules of other to v. Duplicate tracking is not merged,
+// the settings of v stay in effect.
+func (v *Validator) Merge(other *Validator) *Validator {
+	if err := other.Err(); err != nil {
+		v.setErr(err)
+	}
+	for _, r := range other.snapshot() {
+		copied := *r
+		v.add(&copied)
+	}
 	return v
 }
+
+func isEmail(input string) bool {
+	at := strings.LastIndexByte(input, '@')
+	if at < 0 {
+		return false
+	}
+	local, domain := input[:at], input[at+1:]
+	if len(local) == 0 || len(local) > 64 || len(domain) == 0 || len(domain) > 253 {
+		return false
+	}
+
+	for _, part := range strings.Split(local, ".") {
+		if part == "" {
+			return false
+		}
+		for _, r := range part {
+			if !isEmailLocalRune(r) {
+				return false
+			}
+		}
+	}
+
+	labels := strings.Split(domain, ".")
+	if len(labels) < 2 {
+		return false
+	}
+	for _, label := range labels {
+		if !isDomainLabel(label) {
+			return false
+		}
+	}
+	return true
+}
+
+func isEmailLocalRune(r rune) bool {
+	if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+		return true
+	}
+	return strings.ContainsRune("!#$%&'*+-/=?^_`{|}~", r)
+}
+
+func parseMAC(input string) (net.HardwareAddr, bool) {
+	if strings.Contains(input, ".") {
+		return nil, false
+	}
+	addr, err := net.ParseMAC(input)
+	if err != nil || (len(addr) != 6 && len(addr) != 8) {
+		return nil, false
+	}
+	return addr, true
+}
+
+func hostnameLabels(input string) (int, bool) {
+	input = strings.TrimSuffix(input, ".")
+	if input == "" || len(input) > 253 {
+		return 0, false
+	}
+	labels := strings.Split(input, ".")
+	for _, label := range labels {
+		if !isDomainLabel(label) {
+			return 0, false
+		}
+	}
+	return len(labels), true
+}
+
+func isDomainLabel(label string) bool {
+	if len(label) == 0 || len(label) > 63 {
+		return false
+	}
+	if label[0] == '-' || label[len(label)-1] == '-' {
+		return false
+	}
+	for _, r := range label {
+		if !((r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '-') {
+			return false
+		}
+	}
+	return true
+}
+
+func isUUID(input string) bool {
+	if len(input) != 36 {
+		return false
+	}
+	for i, r := range input {
+		switch i {
+		case 8, 13, 18, 23:
+			if r != '-' {
+				return false
+			}
+		default:
+			if !((r >= '0' && r <= '9') || (r >= 'a' && r <= 'f') || (r >= 'A' && r <= 'F')) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+var creditCardBrands = map[string]func(number string) bool{
+	"visa": func(number string) bool {
+		return number[0] == '4' && (len(number) == 13 || len(number) == 16 || len(number) == 19)
+	},
+	"mastercard": func(number string) bool {
+		prefix, _ := strconv.Atoi(number[:4])
+		return len(number) == 16 && ((prefix >= 5100 && prefix <= 5599) || (prefix >= 2221 && prefix <= 2720))
+	},
+	"amex": func(number string) bool {
+		return len(number) == 15 && (strings.HasPrefix(number, "34") || strings.HasPrefix(number, "37"))
+	},
+}
+
+func creditCardNumber(input string) (string, bool) {
+	number := strings.NewReplacer(" ", "", "-", "").Replace(input)
+	if len(number) < 12 || len(number) > 19 {
+		return "", false
+	}
+	sum := 0
+	for i := len(number) - 1; i >= 0; i-- {
+		digit := int(number[i] - '0')
+		if digit < 0 || digit > 9 {
+			return "", false
+		}
+		if (len(number)-i)%2 == 0 {
+			digit *= 2
+			if digit > 9 {
+				digit -= 9
+			}
+		}
+		sum += digit
+	}
+	return number, sum%10 == 0
+}
+
+func containsWord(input string, word string) bool {
+	if word == "" {
+		return false
+	}
+	for offset := 0; offset <= len(input)-len(word); {
+		i := strings.Index(input[offset:], word)
+		if i < 0 {
+			return false
+		}
+		start, end := offset+i, offset+i+len(word)
+		before, _ := utf8.DecodeLastRuneInString(input[:start])
+		after, _ := utf8.DecodeRuneInString(input[end:])
+		if (start == 0 || !isWordRune(before)) && (end == len(input) || !isWordRune(after)) {
+			return true
+		}
+		_, size := utf8.DecodeRuneInString(input[start:])
+		offset = start + size
+	}
+	return false
+}
+
+func isWordRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+func isSlug(input string, separators string) bool {
+	if input == "" {
+		return false
+	}
+	previous := rune(0)
+	for i, r := range input {
+		if strings.ContainsRune(separators, r) {
+			if i == 0 || strings.ContainsRune(separators, previous) {
+				return false
+			}
+		} else if !((r >= 'a' && r <= 'z') || (r >= '0' && r <= '9')) {
+			return false
+		}
+		previous = r
+	}
+	return !strings.ContainsRune(separators, previous)
+}
+
+var phoneSeparators = strings.NewReplacer(" ", "", "-", "", ".", "", "(", "", ")", "")
+
+var phonePatterns = map[string]*regexp.Regexp{
+	"":   regexp.MustCompile(`^\+[1-9][0-9]{1,14}$`),
+	"US": regexp.MustCompile(`^(\+1|1)?[2-9][0-9]{2}[2-9][0-9]{6}$`),
+	"GB": regexp.MustCompile(`^(\+44|0)[1-9][0-9]{8,9}$`),
+	"DE": regexp.MustCompile(`^(\+49|0)[1-9][0-9]{5,13}$`),
+	"HU": regexp.MustCompile(`^(\+36|06)[1-9][0-9]{7,8}$`),
+}
+
+func scriptOf(r rune) string {
+	for name, table := range unicode.Scripts {
+		if name != "Common" && name != "Inherited" && unicode.Is(table, r) {
+			return name
+		}
+	}
+	return "Common"
+}
+
+type semVer struct {
+	core       [3]uint64
+	prerelease []string
+}
+
+func parseSemVer(input string) (semVer, bool) {
+	var version semVer
+	if i := strings.IndexByte(input, '+'); i >= 0 {
+		if !semVerIdentifiers(input[i+1:], false) {
+			return version, false
+		}
+		input = input[:i]
+	}
+	if i := strings.IndexByte(input, '-'); i >= 0 {
+		if !semVerIdentifiers(input[i+1:], true) {
+			return version, false
+		}
+		version.prerelease = strings.Split(input[i+1:], ".")
+		input = input[:i]
+	}
+	parts := strings.Split(input, ".")
+	if len(parts) != 3 {
+		return version, false
+	}
+	for i, part := range parts {
+		if !isSemVerNumber(part) {
+			return version, false
+		}
+		n, err := strconv.ParseUint(part, 10, 64)
+		if err != nil {
+			return version, false
+		}
+		version.core[i] = n
+	}
+	return version, true
+}
+
+func semVerIdentifiers(input string, prerelease bool) bool {
+	for _, identifier := range strings.Split(input, ".") {
+		if identifier == "" {
+			return false
+		}
+		numeric := true
+		for _, r := range identifier {
+			if r >= '0' && r <= '9' {
+				continue
+			}
+			numeric = false
+			if !((r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || r == '-') {
+				return false
+			}
+		}
+		if prerelease && numeric && !isSemVerNumber(identifier) {
+			return false
+		}
+	}
+	return true
+}
+
+func isSemVerNumber(input string) bool {
+	if input == "" || (len(input) > 1 && input[0] == '0') {
+		return false
+	}
+	for _, r := range input {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func compareSemVer(a semVer, b semVer) int {
+	for i := range a.core {
+		if a.core[i] != b.core[i] {
+			if a.core[i] < b.core[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	if len(a.prerelease) == 0 || len(b.prerelease) == 0 {
+		return len(b.prerelease) - len(a.prerelease)
+	}
+	for i := 0; i < len(a.prerelease) && i < len(b.prerelease); i++ {
+		x, y := a.prerelease[i], b.prerelease[i]
+		if x == y {
+			continue
+		}
+		xn, yn := isSemVerNumber(x), isSemVerNumber(y)
+		switch {
+		case xn && yn:
+			if len(x) != len(y) {
+				return len(x) - len(y)
+			}
+			return strings.Compare(x, y)
+		case xn:
+			return -1
+		case yn:
+			return 1
+		}
+		return strings.Compare(x, y)
+	}
+	return len(a.prerelease) - len(b.prerelease)
+}
@@ -1,11 +1,34 @@
 package validator
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
+
+	"golang.org/x/text/language"
+)
+
+type color int
+
+const (
+	colorRed color = iota
+	colorGreen
+	colorBlue
 )
 
+func (c color) String() string {
+	return [...]string{"red", "green", "blue"}[c]
+}
+
 func TestRules(t *testing.T) {
 	var tests = []struct {
 		name      string
@@ -15,6 +38,22 @@ func TestRules(t *testing.T) {
 		approved  []string
 		denied    []string
 	}{
+		{
+			name:      "NotEmpty",
+			validator: NewValidator().NotEmpty(),
+			ruleType:  NotEmpty,
+			reason:    "must not be empty",
+			approved:  []string{"a", " ", "\t"},
+			denied:    []string{""},
+		},
+		{
+			name:      "Required",
+			validator: NewValidator().Required(),
+			ruleType:  Required,
+			reason:    "must not be blank",
+			approved:  []string{"a", " a "},
+			denied:    []string{"", " ", "\t\n"},
+		},
 		{
 			name:      "StartsWith",
 			validator: NewValidator().StartsWith("123"),
@@ -31,13 +70,61 @@ func TestRules(t *testing.T) {
 			approved:  []string{"aaa123", "bbb123", "ccc123"},
 			denied:    []string{"123aaa", "123bbb", "123ccc"},
 		},
+		{
+			name:      "StartsWithAny",
+			validator: NewValidator().StartsWithAny([]string{"img_", "doc_"}),
+			ruleType:  StartsWithAny,
+			reason:    "starts with any of [img_ doc_]",
+			approved:  []string{"img_001.png", "doc_report.pdf"},
+			denied:    []string{"", "vid_001.mp4", "my_img_001.png"},
+		},
+		{
+			name:      "EndsWithAny",
+			validator: NewValidator().EndsWithAny([]string{".png", ".jpg"}),
+			ruleType:  EndsWithAny,
+			reason:    "ends with any of [.png .jpg]",
+			approved:  []string{"a.png", "b.jpg"},
+			denied:    []string{"", "c.gif", "a.png.zip"},
+		},
+		{
+			name:      "StartsWithFold",
+			validator: NewValidator().StartsWithFold("Abc"),
+			ruleType:  StartsWithFold,
+			reason:    "starts with Abc (case-insensitive)",
+			approved:  []string{"abc123", "ABC123", "aBc"},
+			denied:    []string{"123abc", "ab"},
+		},
+		{
+			name:      "EndsWithFold",
+			validator: NewValidator().EndsWithFold("Ünü"),
+			ruleType:  EndsWithFold,
+			reason:    "ends with Ünü (case-insensitive)",
+			approved:  []string{"123ünü", "123ÜNÜ"},
+			denied:    []string{"ünü123", "unu"},
+		},
+		{
+			name:      "NotStartsWith",
+			validator: NewValidator().NotStartsWith("_"),
+			ruleType:  NotStartsWith,
+			reason:    "does not start with _",
+			approved:  []string{"aaa", "a_a", "aa_"},
+			denied:    []string{"_aa", "__a"},
+		},
+		{
+			name:      "NotEndsWith",
+			validator: NewValidator().NotEndsWith("_"),
+			ruleType:  NotEndsWith,
+			reason:    "does not end with _",
+			approved:  []string{"aaa", "a_a", "_aa"},
+			denied:    []string{"aa_", "a__"},
+		},
 		{
 			name:      "LongerThan",
 			validator: NewValidator().LongerThan(4),
 			ruleType:  LongerThan,
 			reason:    "longer than 4",
-			approved:  []string{"aaaaa", "aaaaa", "aaaaaa"},
-			denied:    []string{"a", "aa", "aaa", "aaaa"},
+			approved:  []string{"aaaaa", "aaaaa", "aaaaaa", "日本語です!"},
+			denied:    []string{"a", "aa", "aaa", "aaaa", "日本語", "café"},
 		},
 		{
 			name:      "LongerThanOrEqual",
@@ -52,8 +139,8 @@ func TestRules(t *testing.T) {
 			validator: NewValidator().ShorterThan(4),
 			ruleType:  ShorterThan,
 			reason:    "shorter than 4",
-			approved:  []string{"a", "aa", "aaa"},
-			denied:    []string{"aaaa", "aaaaa", "aaaaaa"},
+			approved:  []string{"a", "aa", "aaa", "日本語"},
+			denied:    []string{"aaaa", "aaaaa", "aaaaaa", "café"},
 		},
 		{
 			name:      "ShorterThanOrEqual",
@@ -63,6 +150,54 @@ func TestRules(t *testing.T) {
 			approved:  []string{"a", "aa", "aaa", "aaaa", "aaaaa"},
 			denied:    []string{"aaaaaa", "aaaaaaa", "aaaaaaaa"},
 		},
+		{
+			name:      "MinLength",
+			validator: NewValidator().MinLength(5),
+			ruleType:  MinLength,
+			reason:    "at least 5 characters",
+			approved:  []string{"aaaaa", "aaaaaa", "日本語です"},
+			denied:    []string{"", "aaaa", "日本語"},
+		},
+		{
+			name:      "MaxLength",
+			validator: NewValidator().MaxLength(3),
+			ruleType:  MaxLength,
+			reason:    "at most 3 characters",
+			approved:  []string{"", "aaa", "日本語"},
+			denied:    []string{"aaaa", "café!"},
+		},
+		{
+			name:      "LengthEqual",
+			validator: NewValidator().LengthEqual(2),
+			ruleType:  LengthEqual,
+			reason:    "exactly 2 characters",
+			approved:  []string{"HU", "US", "日本"},
+			denied:    []string{"", "a", "abc", "日本語"},
+		},
+		{
+			name:      "LengthDivisibleBy",
+			validator: NewValidator().LengthDivisibleBy(3),
+			ruleType:  LengthDivisibleBy,
+			reason:    "length divisible by 3",
+			approved:  []string{"", "aaa", "aaaaaa", "日本語"},
+			denied:    []string{"a", "aa", "aaaa", "café"},
+		},
+		{
+			name:      "LongerThanBytes",
+			validator: NewValidator().LongerThanBytes(4),
+			ruleType:  LongerThanBytes,
+			reason:    "longer than 4 bytes",
+			approved:  []string{"aaaaa", "café", "日本語"},
+			denied:    []string{"a", "aaaa", "caf"},
+		},
+		{
+			name:      "ShorterThanBytes",
+			validator: NewValidator().ShorterThanBytes(4),
+			ruleType:  ShorterThanBytes,
+			reason:    "shorter than 4 bytes",
+			approved:  []string{"a", "aaa", "é"},
+			denied:    []string{"aaaa", "café", "日本語"},
+		},
 		{
 			name:      "Contains",
 			validator: NewValidator().Contains("123"),
@@ -71,6 +206,62 @@ func TestRules(t *testing.T) {
 			approved:  []string{"aaa123aaa", "bbb123bbb", "ccc123ccc"},
 			denied:    []string{"aaa", "bbb", "ccc"},
 		},
+		{
+			name:      "ContainsFold",
+			validator: NewValidator().ContainsFold("abc"),
+			ruleType:  ContainsFold,
+			reason:    "contains abc (case-insensitive)",
+			approved:  []string{"xxABCxx", "abc", "xAbC"},
+			denied:    []string{"ab c", "xyz"},
+		},
+		{
+			name:      "ContainsWord",
+			validator: NewValidator().ContainsWord("cat"),
+			ruleType:  ContainsWord,
+			reason:    "contains the word cat",
+			approved:  []string{"cat", "a cat", "cat!", "the (cat) sat", "category, cat", "dog-cat"},
+			denied:    []string{"", "category", "concat", "cats", "Cat", "cat_food", "çat", "catédral"},
+		},
+		{
+			name:      "ContainsAny",
+			validator: NewValidator().ContainsAny([]string{"cat", "dog"}),
+			ruleType:  ContainsAny,
+			reason:    "contains any of [cat dog]",
+			approved:  []string{"cat", "hotdog", "catdog"},
+			denied:    []string{"", "bird", "ca t"},
+		},
+		{
+			name:      "ContainsAll",
+			validator: NewValidator().ContainsAll([]string{"cat", "dog"}),
+			ruleType:  ContainsAll,
+			reason:    "contains all of [cat dog]",
+			approved:  []string{"catdog", "dog and cat"},
+			denied:    []string{"", "cat", "hotdog"},
+		},
+		{
+			name:      "ContainsAtLeast",
+			validator: NewValidator().ContainsAtLeast("{{", 2),
+			ruleType:  ContainsAtLeast,
+			reason:    "contains {{ at least 2 times",
+			approved:  []string{"{{a}} {{b}}", "{{{{", "{{a}}{{b}}{{c}}"},
+			denied:    []string{"", "{{a}}", "{a} {b}"},
+		},
+		{
+			name:      "ContainsExactly",
+			validator: NewValidator().ContainsExactly("{{", 2),
+			ruleType:  ContainsExactly,
+			reason:    "contains {{ exactly 2 times",
+			approved:  []string{"{{a}} {{b}}"},
+			denied:    []string{"", "{{a}}", "{{a}}{{b}}{{c}}"},
+		},
+		{
+			name:      "NotContains",
+			validator: NewValidator().NotContains(" "),
+			ruleType:  NotContains,
+			reason:    "does not contain  ",
+			approved:  []string{"aaa", "bbb"},
+			denied:    []string{"a a", " aa", "aa "},
+		},
 		{
 			name:      "ContainsACharacter",
 			validator: NewValidator().ContainsACharacter(),
@@ -87,6 +278,118 @@ func TestRules(t *testing.T) {
 			approved:  []string{"111", "222", "333"},
 			denied:    []string{"aaa", "bbb", "ccc"},
 		},
+		{
+			name:      "IsNumeric",
+			validator: NewValidator().IsNumeric(),
+			ruleType:  IsNumeric,
+			reason:    "is numeric",
+			approved:  []string{"0", "1234", "007"},
+			denied:    []string{"", "12a", "-1", "1.5", "١٢٣"},
+		},
+		{
+			name:      "NumericRange",
+			validator: NewValidator().NumericRange(-5, 40),
+			ruleType:  NumericRange,
+			reason:    "integer between -5 and 40",
+			approved:  []string{"-5", "0", "007", "40", "+12"},
+			denied:    []string{"", "-6", "41", "abc", "1.5", "1e2"},
+		},
+		{
+			name:      "ValueDivisibleBy",
+			validator: NewValidator().ValueDivisibleBy(5),
+			ruleType:  ValueDivisibleBy,
+			reason:    "integer divisible by 5",
+			approved:  []string{"0", "5", "-10", "100"},
+			denied:    []string{"", "3", "abc", "5.0"},
+		},
+		{
+			name:      "IsAlphanumeric",
+			validator: NewValidator().IsAlphanumeric(),
+			ruleType:  IsAlphanumeric,
+			reason:    "is alphanumeric (unicode letters and digits)",
+			approved:  []string{"abc123", "ABC", "123", "café", "日本語"},
+			denied:    []string{"", "a b", "a-b", "a_b", "abc!"},
+		},
+		{
+			name:      "IsUppercase",
+			validator: NewValidator().IsUppercase(),
+			ruleType:  IsUppercase,
+			reason:    "is uppercase",
+			approved:  []string{"HU", "ABC-123", "ÁÉ"},
+			denied:    []string{"", "Hu", "hu", "123", "-"},
+		},
+		{
+			name:      "IsLowercase",
+			validator: NewValidator().IsLowercase(),
+			ruleType:  IsLowercase,
+			reason:    "is lowercase",
+			approved:  []string{"my-slug", "abc123", "áé"},
+			denied:    []string{"", "My-slug", "ABC", "123", "-"},
+		},
+		{
+			name:      "NoWhitespace",
+			validator: NewValidator().NoWhitespace(),
+			ruleType:  NoWhitespace,
+			reason:    "contains no whitespace",
+			approved:  []string{"", "abc", "a-b_c"},
+			denied:    []string{"a b", "a\tb", "a\nb", " ab", "a\u00a0b"},
+		},
+		{
+			name:      "Trimmed",
+			validator: NewValidator().Trimmed(),
+			ruleType:  Trimmed,
+			reason:    "has no leading or trailing whitespace",
+			approved:  []string{"", "abc", "a b"},
+			denied:    []string{" abc", "abc ", "\tabc", "abc\n"},
+		},
+		{
+			name:      "IsPrintableASCII",
+			validator: NewValidator().IsPrintableASCII(),
+			ruleType:  IsPrintableASCII,
+			reason:    "printable ascii only",
+			approved:  []string{"", "abc", "Hello, World!", "~ {}"},
+			denied:    []string{"tab\there", "line\n", "caf\u00e9", "\x7f", "日本"},
+		},
+		{
+			name:      "RunesInRange",
+			validator: NewValidator().RunesInRange(0, 127),
+			ruleType:  RunesInRange,
+			reason:    "runes in range U+0000 to U+007F",
+			approved:  []string{"", "abc", "tab\there", "\x7f"},
+			denied:    []string{"café", "日本", "\u0080"},
+		},
+		{
+			name:      "EqualTo",
+			validator: NewValidator().EqualTo("token"),
+			ruleType:  EqualTo,
+			reason:    "equal to token",
+			approved:  []string{"token"},
+			denied:    []string{"", "Token", "token "},
+		},
+		{
+			name:      "NotEqualTo",
+			validator: NewValidator().NotEqualTo("admin"),
+			ruleType:  NotEqualTo,
+			reason:    "not equal to admin",
+			approved:  []string{"", "Admin", "admin1"},
+			denied:    []string{"admin"},
+		},
+		{
+			name:      "LexBetween",
+			validator: NewValidator().LexBetween("b", "d"),
+			ruleType:  LexBetween,
+			reason:    "between b and d inclusive",
+			approved:  []string{"b", "ba", "c", "czz", "d"},
+			denied:    []string{"", "a", "azz", "da", "e"},
+		},
+		{
+			name:      "LexBetweenExclusive",
+			validator: NewValidator().LexBetweenExclusive("b", "d"),
+			ruleType:  LexBetweenExclusive,
+			reason:    "between b and d exclusive",
+			approved:  []string{"ba", "c", "czz"},
+			denied:    []string{"", "a", "b", "d", "da"},
+		},
 		{
 			name:      "Ignore",
 			validator: NewValidator().Ignore("aaa"),
@@ -95,6 +398,74 @@ func TestRules(t *testing.T) {
 			approved:  []string{"bbb", "ccc"},
 			denied:    []string{"aaa"},
 		},
+		{
+			name:      "IgnoreFold",
+			validator: NewValidator().IgnoreFold("ABC"),
+			ruleType:  IgnoreFold,
+			reason:    "ignore ABC (case-insensitive)",
+			approved:  []string{"", "abcd", "xyz"},
+			denied:    []string{"abc", "ABC", "aBc"},
+		},
+		{
+			name: "Not",
+			validator: NewValidator().Not(func(v *Validator) *Validator {
+				return v.StartsWith("a").EndsWith("z")
+			}),
+			ruleType: Not,
+			reason:   "not starts with a and ends with z",
+			approved: []string{"abc", "xyz", "bbb"},
+			denied:   []string{"az", "abcz"},
+		},
+		{
+			name: "Any",
+			validator: NewValidator().Any(
+				NewValidator().Email(),
+				NewValidator().StartsWith("+").ContainsANumber(),
+			),
+			ruleType: Any,
+			reason:   "valid email address or starts with + and contains a number",
+			approved: []string{"foo@bar.com", "+3612345678"},
+			denied:   []string{"foo", "+abc", "123"},
+		},
+		{
+			name:      "OneOf",
+			validator: NewValidator().OneOf([]string{"red", "green", ""}),
+			ruleType:  OneOf,
+			reason:    "one of [red green ]",
+			approved:  []string{"red", "green", ""},
+			denied:    []string{"blue", "Red", "redd"},
+		},
+		{
+			name:      "OneOfStringers",
+			validator: NewValidator().OneOfStringers(colorRed, colorGreen),
+			ruleType:  OneOf,
+			reason:    "one of [red green]",
+			approved:  []string{"red", "green"},
+			denied:    []string{"", "blue", "Red", "0"},
+		},
+		{
+			name:      "OneOfFold",
+			validator: NewValidator().OneOfFold([]string{"Red", "GREEN"}),
+			ruleType:  OneOfFold,
+			reason:    "one of [Red GREEN] (case-insensitive)",
+			approved:  []string{"red", "RED", "Red", "green", "gReEn"},
+			denied:    []string{"", "blue", "reds"},
+		},
+		{
+			name: "When",
+			validator: NewValidator().When(
+				func(input string) bool {
+					return strings.HasPrefix(input, "v")
+				},
+				func(v *Validator) *Validator {
+					return v.Regexp("^v[0-9]+$")
+				},
+			),
+			ruleType: When,
+			reason:   "when condition holds regexp ^v[0-9]+$",
+			approved: []string{"v1", "v123", "abc", ""},
+			denied:   []string{"v", "vabc", "v1a"},
+		},
 		{
 			name:      "Regexp",
 			validator: NewValidator().Regexp("t([a-z]+)t"),
@@ -103,6 +474,14 @@ func TestRules(t *testing.T) {
 			approved:  []string{"test", "talent"},
 			denied:    []string{"aaa", "bbb", "ccc"},
 		},
+		{
+			name:      "Matches",
+			validator: NewValidator().Matches(regexp.MustCompile("^[0-9]{3}$")),
+			ruleType:  Regexp,
+			reason:    "regexp ^[0-9]{3}$",
+			approved:  []string{"123", "000"},
+			denied:    []string{"12", "1234", "abc"},
+		},
 		{
 			name:      "InvalidRegexp",
 			validator: NewValidator().Regexp("[0-9]++"),
@@ -111,6 +490,301 @@ func TestRules(t *testing.T) {
 			approved:  []string{},
 			denied:    []string{"aaa", "bbb", "ccc"},
 		},
+		{
+			name:      "MatchesOneOf",
+			validator: NewValidator().MatchesOneOf([]string{"^[0-9]{4}$", "^[A-Z]{2}-[0-9]+$"}),
+			ruleType:  MatchesOneOf,
+			reason:    "matches one of [^[0-9]{4}$ ^[A-Z]{2}-[0-9]+$]",
+			approved:  []string{"1234", "AB-1", "XY-2023"},
+			denied:    []string{"", "123", "ab-1", "AB-", "12345"},
+		},
+		{
+			name:      "Email",
+			validator: NewValidator().Email(),
+			ruleType:  Email,
+			reason:    "valid email address",
+			approved:  []string{"foo@bar.com", "a@b.co", "first.last+tag@sub.example.org"},
+			denied:    []string{"", "foo", "@bar.com", "foo@", "foo bar@baz.com", "foo@bar.com.", ".foo@bar.com", "foo..bar@baz.com", "foo@bar", "foo@-bar.com"},
+		},
+		{
+			name:      "EmailStrict",
+			validator: NewValidator().EmailStrict(),
+			ruleType:  EmailStrict,
+			reason:    "valid email address (strict, no display name)",
+			approved:  []string{"bob@x.com", "first.last@example.org"},
+			denied:    []string{"", "Bob <bob@x.com>", "<bob@x.com>", "\"Bob\" <bob@x.com>", "bob", " bob@x.com"},
+		},
+		{
+			name:      "EmailWithName",
+			validator: NewValidator().EmailWithName(),
+			ruleType:  EmailWithName,
+			reason:    "valid email address (display name allowed)",
+			approved:  []string{"bob@x.com", "Bob <bob@x.com>", "<bob@x.com>"},
+			denied:    []string{"", "bob", "Bob <bob>"},
+		},
+		{
+			name:      "URL",
+			validator: NewValidator().URL(),
+			ruleType:  URL,
+			reason:    "valid URL",
+			approved:  []string{"https://example.com", "ftp://example.com/file", "http://localhost:8080/path?q=1"},
+			denied:    []string{"", "example.com", "/relative/path", "https://", "mailto:foo@bar.com"},
+		},
+		{
+			name:      "URLWithSchemes",
+			validator: NewValidator().URL("http", "https"),
+			ruleType:  URL,
+			reason:    "valid URL with scheme http or https",
+			approved:  []string{"https://example.com", "HTTP://example.com"},
+			denied:    []string{"ftp://example.com", "example.com"},
+		},
+		{
+			name:      "IsMAC",
+			validator: NewValidator().IsMAC(),
+			ruleType:  IsMAC,
+			reason:    "valid mac address",
+			approved:  []string{"00:00:5e:00:53:01", "00-00-5E-00-53-01", "02:00:5e:10:00:00:00:01", "02-00-5E-10-00-00-00-01"},
+			denied:    []string{"", "00:00:5e:00:53", "0000.5e00.5301", "00:00:5e:00:53:zz", "00:00:5e:00:53:01:02", "00:00:00:00:fe:80:00:00:00:00:00:00:02:00:5e:10:00:00:00:01"},
+		},
+		{
+			name:      "IsHostname",
+			validator: NewValidator().IsHostname(),
+			ruleType:  IsHostname,
+			reason:    "valid hostname",
+			approved:  []string{"localhost", "example.com", "example.com.", "a-b.c-d.e", "1.2.3", strings.Repeat("a", 63), strings.Repeat("a.", 126) + "a"},
+			denied:    []string{"", ".", "-a.com", "a-.com", "a..com", "a_b.com", "exa mple.com", strings.Repeat("a", 64), strings.Repeat("a.", 127) + "a", "münchen.de"},
+		},
+		{
+			name:      "IsFQDN",
+			validator: NewValidator().IsFQDN(),
+			ruleType:  IsFQDN,
+			reason:    "fully qualified domain name",
+			approved:  []string{"example.com", "example.com.", "localhost.", "a.b.c"},
+			denied:    []string{"", "localhost", ".", "a..b", "-a.com"},
+		},
+		{
+			name:      "IsIP",
+			validator: NewValidator().IsIP(),
+			ruleType:  IsIP,
+			reason:    "is a valid IP address",
+			approved:  []string{"127.0.0.1", "192.168.1.254", "::1", "2001:db8::68"},
+			denied:    []string{"", "256.0.0.1", "1.2.3", "localhost", "2001:db8:::68"},
+		},
+		{
+			name:      "IsIPv4",
+			validator: NewValidator().IsIPv4(),
+			ruleType:  IsIPv4,
+			reason:    "is a valid IPv4 address",
+			approved:  []string{"127.0.0.1", "0.0.0.0", "255.255.255.255"},
+			denied:    []string{"", "256.0.0.1", "::1", "::ffff:127.0.0.1"},
+		},
+		{
+			name:      "IsIPv6",
+			validator: NewValidator().IsIPv6(),
+			ruleType:  IsIPv6,
+			reason:    "is a valid IPv6 address",
+			approved:  []string{"::1", "2001:db8::68", "::ffff:127.0.0.1"},
+			denied:    []string{"", "127.0.0.1", "2001:db8:::68"},
+		},
+		{
+			name:      "Date",
+			validator: NewValidator().Date("2006-01-02"),
+			ruleType:  Date,
+			reason:    "matches date layout 2006-01-02",
+			approved:  []string{"2021-02-28", "2020-02-29"},
+			denied:    []string{"", "2021-02-30", "2021-2-28", "28/02/2021"},
+		},
+		{
+			name: "DateBetween",
+			validator: NewValidator().DateBetween(
+				"2006-01-02",
+				time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+				time.Date(2021, 12, 31, 0, 0, 0, 0, time.UTC),
+			),
+			ruleType: DateBetween,
+			reason:   "date with layout 2006-01-02 between 2021-01-01 and 2021-12-31",
+			approved: []string{"2021-01-01", "2021-06-15", "2021-12-31"},
+			denied:   []string{"2020-12-31", "2022-01-01", "2021-02-30"},
+		},
+		{
+			name:      "IsDuration",
+			validator: NewValidator().IsDuration(),
+			ruleType:  IsDuration,
+			reason:    "valid duration",
+			approved:  []string{"5m", "1h30m", "300ms", "-2s", "0"},
+			denied:    []string{"", "5", "1d", "abc", "1h30"},
+		},
+		{
+			name:      "DurationBetween",
+			validator: NewValidator().DurationBetween(time.Minute, time.Hour),
+			ruleType:  DurationBetween,
+			reason:    "duration between 1m0s and 1h0m0s",
+			approved:  []string{"1m", "5m", "1h", "59m59s"},
+			denied:    []string{"", "59s", "1h1s", "2h", "abc"},
+		},
+		{
+			name:      "IsJSON",
+			validator: NewValidator().IsJSON(),
+			ruleType:  IsJSON,
+			reason:    "is valid JSON",
+			approved:  []string{`{"a":1}`, `[1,2,3]`, `"text"`, `null`, ` {} `},
+			denied:    []string{"", "   ", "{", `{"a":}`, "undefined"},
+		},
+		{
+			name:      "IsHex",
+			validator: NewValidator().IsHex(),
+			ruleType:  IsHex,
+			reason:    "is hexadecimal",
+			approved:  []string{"0", "deadBEEF", "abc"},
+			denied:    []string{"", "0x1f", "xyz", "12 34"},
+		},
+		{
+			name:      "IsHexBytes",
+			validator: NewValidator().IsHexBytes(),
+			ruleType:  IsHexBytes,
+			reason:    "is hex encoded bytes",
+			approved:  []string{"00", "deadBEEF"},
+			denied:    []string{"", "abc", "zz"},
+		},
+		{
+			name:      "IsBase64",
+			validator: NewValidator().IsBase64(),
+			ruleType:  IsBase64,
+			reason:    "is base64 encoded",
+			approved:  []string{"aGVsbG8=", "Pz8/Pw==", "YWJj"},
+			denied:    []string{"", "aGVsbG8", "Pz8_Pw==", "!!!!"},
+		},
+		{
+			name:      "IsBase64URL",
+			validator: NewValidator().IsBase64URL(),
+			ruleType:  IsBase64URL,
+			reason:    "is URL-safe base64 encoded",
+			approved:  []string{"aGVsbG8=", "Pz8_Pw=="},
+			denied:    []string{"", "Pz8/Pw==", "aGVsbG8"},
+		},
+		{
+			name:      "IsUUID",
+			validator: NewValidator().IsUUID(),
+			ruleType:  IsUUID,
+			reason:    "is a UUID",
+			approved:  []string{"123e4567-e89b-12d3-a456-426614174000", "00000000-0000-0000-0000-000000000000", "F47AC10B-58CC-4372-A567-0E02B2C3D479"},
+			denied:    []string{"", "123e4567e89b12d3a456426614174000", "{123e4567-e89b-12d3-a456-426614174000}", "urn:uuid:123e4567-e89b-12d3-a456-426614174000", "123e4567-e89b-12d3-a456-42661417400g"},
+		},
+		{
+			name:      "IsUUIDVersion",
+			validator: NewValidator().IsUUIDVersion(4),
+			ruleType:  IsUUIDVersion,
+			reason:    "is a version 4 UUID",
+			approved:  []string{"f47ac10b-58cc-4372-a567-0e02b2c3d479", "f47ac10b-58cc-4372-8567-0e02b2c3d479"},
+			denied:    []string{"123e4567-e89b-12d3-a456-426614174000", "f47ac10b-58cc-4372-c567-0e02b2c3d479", "not-a-uuid"},
+		},
+		{
+			name:      "IsCreditCard",
+			validator: NewValidator().IsCreditCard(),
+			ruleType:  IsCreditCard,
+			reason:    "passes Luhn check",
+			approved:  []string{"4111111111111111", "4111 1111 1111 1111", "5500-0000-0000-0004", "378282246310005"},
+			denied:    []string{"", "4111111111111112", "4111-1111-1111-111a", "1234", "00000000000000000000"},
+		},
+		{
+			name:      "IsCreditCardBrand",
+			validator: NewValidator().IsCreditCardBrand("visa"),
+			ruleType:  IsCreditCardBrand,
+			reason:    "visa card number passing Luhn check",
+			approved:  []string{"4111111111111111", "4012 8888 8888 1881"},
+			denied:    []string{"5500000000000004", "378282246310005", "4111111111111112"},
+		},
+		{
+			name:      "IsCreditCardBrandMastercard",
+			validator: NewValidator().IsCreditCardBrand("mastercard"),
+			ruleType:  IsCreditCardBrand,
+			reason:    "mastercard card number passing Luhn check",
+			approved:  []string{"5500000000000004", "2221000000000009"},
+			denied:    []string{"4111111111111111", "378282246310005"},
+		},
+		{
+			name:      "IsCreditCardBrandAmex",
+			validator: NewValidator().IsCreditCardBrand("amex"),
+			ruleType:  IsCreditCardBrand,
+			reason:    "amex card number passing Luhn check",
+			approved:  []string{"378282246310005", "3714 496353 98431"},
+			denied:    []string{"4111111111111111", "5500000000000004"},
+		},
+		{
+			name:      "IsSlug",
+			validator: NewValidator().IsSlug(),
+			ruleType:  IsSlug,
+			reason:    "is a slug",
+			approved:  []string{"hello", "hello-world", "post-2021-01"},
+			denied:    []string{"", "-hello", "hello-", "hello--world", "hello_world", "Hello", "hello world"},
+		},
+		{
+			name:      "IsSlugUnderscore",
+			validator: NewValidator().IsSlugUnderscore(),
+			ruleType:  IsSlugUnderscore,
+			reason:    "is a slug (underscores allowed)",
+			approved:  []string{"hello", "hello_world", "hello-big_world"},
+			denied:    []string{"", "_hello", "hello_", "hello__world", "hello-_world", "Hello"},
+		},
+		{
+			name:      "IsGoIdentifier",
+			validator: NewValidator().IsGoIdentifier(),
+			ruleType:  IsGoIdentifier,
+			reason:    "valid go identifier",
+			approved:  []string{"a", "_", "_private", "Exported", "camelCase2", "héllo", "string"},
+			denied:    []string{"", "2fast", "kebab-case", "with space", "func", "type", "range"},
+		},
+		{
+			name:      "IsPhone",
+			validator: NewValidator().IsPhone(""),
+			ruleType:  IsPhone,
+			reason:    "is an E.164 phone number",
+			approved:  []string{"+14155552671", "+36301234567", "+442071838750"},
+			denied:    []string{"", "14155552671", "+0123456", "+1234567890123456", "+1 415 555 2671"},
+		},
+		{
+			name:      "IsPhoneUS",
+			validator: NewValidator().IsPhone("us"),
+			ruleType:  IsPhone,
+			reason:    "is a US phone number",
+			approved:  []string{"(415) 555-2671", "415.555.2671", "+1 415 555 2671", "14155552671"},
+			denied:    []string{"", "555-2671", "(015) 555-2671", "+44 20 7183 8750"},
+		},
+		{
+			name:      "IsPhoneHU",
+			validator: NewValidator().IsPhone("HU"),
+			ruleType:  IsPhone,
+			reason:    "is a HU phone number",
+			approved:  []string{"06 30 123 4567", "+36 1 234 5678", "+36301234567"},
+			denied:    []string{"", "30 123 4567", "+37 30 123 4567"},
+		},
+		{
+			name:      "IsSemVer",
+			validator: NewValidator().IsSemVer(),
+			ruleType:  IsSemVer,
+			reason:    "valid semantic version",
+			approved:  []string{"0.0.0", "1.2.3", "10.20.30", "1.0.0-alpha", "1.0.0-alpha.1", "1.0.0-0.3.7", "1.0.0+20130313144700", "1.0.0-beta+exp.sha.5114f85", "1.0.0-x-y-z.--"},
+			denied:    []string{"", "1", "1.2", "1.2.3.4", "01.2.3", "1.02.3", "v1.2.3", "1.2.3-", "1.2.3-01", "1.2.3+", "1.2.3-alpha..1", "1.2.3-alpha_1"},
+		},
+		{
+			name:      "SemVerAtLeast",
+			validator: NewValidator().SemVerAtLeast("1.2.0"),
+			ruleType:  SemVerAtLeast,
+			reason:    "semantic version at least 1.2.0",
+			approved:  []string{"1.2.0", "1.2.0+build", "1.2.1", "1.10.0", "2.0.0", "2.0.0-alpha"},
+			denied:    []string{"", "1.1.9", "1.2.0-rc.1", "0.9.0", "abc"},
+		},
+		{
+			name: "Or",
+			validator: NewValidator().Or(
+				func(v *Validator) *Validator { return v.StartsWith("A") },
+				func(v *Validator) *Validator { return v.StartsWith("B").LongerThan(2) },
+			),
+			ruleType: Or,
+			reason:   "starts with A or starts with B and longer than 2",
+			approved: []string{"A", "Abc", "Bcd"},
+			denied:   []string{"", "Bc", "abc", "Cde"},
+		},
 		{
 			name: "Custom",
 			validator: NewValidator().Custom("custom reason", func(input string) bool {
@@ -160,39 +834,1918 @@ func TestRules(t *testing.T) {
 	}
 }
 
-func TestIgnoreDuplicates(t *testing.T) {
-	validator := NewValidator().IgnoreDuplicatesFor(time.Millisecond)
+func TestCustomWithReason(t *testing.T) {
+	calls := 0
+	validator := NewValidator().CustomWithReason(func(input string) (bool, string) {
+		calls++
+		if len(input) > 3 {
+			return false, fmt.Sprintf("length %d exceeds maximum of 3", len(input))
+		}
+		return true, ""
+	})
 
 	result := validator.Validate("aaa")
 	if !result.Approval {
 		t.Fatal("approval expected")
 	}
 
-	result = validator.Validate("aaa")
+	result = validator.Validate("aaaaa")
 	if result.Approval {
 		t.Fatal("deny expected")
 	}
 
-	time.Sleep(2 * time.Millisecond)
+	if result.RuleType != Custom {
+		t.Fatal("invalid rule type", result.RuleType, Custom)
+	}
 
-	result = validator.Validate("aaa")
-	if !result.Approval {
-		t.Fatal("approval expected")
+	if result.Reason != "length 5 exceeds maximum of 3" {
+		t.Fatal("invalid reason", result.Reason)
 	}
 
-	result = validator.Validate("aaa")
+	if calls != 2 {
+		t.Fatal("one call per validation expected", calls)
+	}
+}
+
+func TestValidateContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	validator := NewValidator().
+		Custom("cancels", func(input string) bool {
+			cancel()
+			return true
+		}).
+		Custom("never reached", func(input string) bool {
+			t.Fatal("rule after cancellation evaluated")
+			return false
+		})
+
+	result := validator.ValidateContext(ctx, "aaa")
 	if result.Approval {
 		t.Fatal("deny expected")
 	}
 
-	validator.StopIgnoringDuplicates()
+	if result.RuleType != Canceled {
+		t.Fatal("invalid rule type", result.RuleType, Canceled)
+	}
 
-	result = validator.Validate("aaa")
-	if !result.Approval {
+	if result.Reason != context.Canceled.Error() {
+		t.Fatal("invalid reason", result.Reason)
+	}
+}
+
+func TestLabeled(t *testing.T) {
+	validator := NewValidator().
+		Labeled("nothing to label").
+		Custom("has upper", func(input string) bool {
+			return strings.ToLower(input) != input
+		}).
+		Labeled("password-upper").
+		Custom("has lower", func(input string) bool {
+			return strings.ToUpper(input) != input
+		}).
+		Labeled("password-lower")
+
+	result := validator.Validate("abc")
+	if result.Label != "password-upper" {
+		t.Fatal("invalid label", result.Label)
+	}
+
+	result = validator.Validate("ABC")
+	if result.Label != "password-lower" {
+		t.Fatal("invalid label", result.Label)
+	}
+
+	result = validator.Validate("Abc")
+	if result.Label != "" {
+		t.Fatal("label unexpected", result.Label)
+	}
+}
+
+func TestAsError(t *testing.T) {
+	validator := NewValidator().StartsWith("123")
+
+	if err := validator.Validate("123abc").AsError(); err != nil {
+		t.Fatal("no error expected", err)
+	}
+
+	err := validator.Validate("abc").AsError()
+	if err == nil {
+		t.Fatal("error expected")
+	}
+
+	if err.Error() != "\"starts with 123\" is not met by \"abc\"" {
+		t.Fatal("invalid error", err)
+	}
+
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatal("validation error expected")
+	}
+
+	if validationErr.RuleType != StartsWith {
+		t.Fatal("invalid rule type", validationErr.RuleType)
+	}
+}
+
+func TestRemoveRule(t *testing.T) {
+	validator := NewValidator().
+		StartsWith("a").
+		IgnoreAll([]string{"abc", "abd"}).
+		LongerThan(2).
+		Labeled("length").
+		EndsWith("z")
+
+	validator.RemoveRule(Ignore)
+
+	expected := []RuleType{StartsWith, LongerThan, EndsWith}
+	for i, ruleType := range validator.Rules() {
+		if ruleType != expected[i] {
+			t.Fatal("invalid rule type", ruleType, expected[i])
+		}
+	}
+
+	validator.RemoveRuleByLabel("length").RemoveRule(EndsWith)
+
+	if validator.RuleCount() != 1 {
+		t.Fatal("invalid rule count", validator.RuleCount())
+	}
+
+	if !validator.Validate("a").Approval {
 		t.Fatal("approval expected")
 	}
 }
 
+func TestSortRulesByCost(t *testing.T) {
+	validator := NewValidator().
+		Custom("custom reason", func(input string) bool {
+			return true
+		}).
+		Regexp("^a").
+		LongerThan(2).
+		StartsWith("a").
+		Cost(50).
+		EndsWith("z").
+		SortRulesByCost()
+
+	expected := []RuleType{LongerThan, EndsWith, Regexp, StartsWith, Custom}
+	for i, ruleType := range validator.Rules() {
+		if ruleType != expected[i] {
+			t.Fatal("invalid rule type", i, ruleType, expected[i])
+		}
+	}
+}
+
+func TestWithMessage(t *testing.T) {
+	validator := NewValidator().
+		LongerThan(5).
+		WithMessage("Password too short").
+		ContainsANumber().
+		WithMessage("{input} has no number").
+		CustomWithReason(func(input string) (bool, string) {
+			return input != "secret1", "dynamic reason"
+		}).
+		WithMessage("Password too common")
+
+	result := validator.Validate("abc")
+	if result.Reason != "Password too short" {
+		t.Fatal("invalid reason", result.Reason)
+	}
+
+	if result.RuleType != LongerThan {
+		t.Fatal("invalid rule type", result.RuleType)
+	}
+
+	result = validator.Validate("abcdef")
+	if result.Reason != "abcdef has no number" {
+		t.Fatal("invalid reason", result.Reason)
+	}
+
+	result = validator.Validate("secret1")
+	if result.Reason != "Password too common" {
+		t.Fatal("invalid reason", result.Reason)
+	}
+}
+
+func TestDenyByDefault(t *testing.T) {
+	validator := NewValidator().DenyByDefault()
+
+	result := validator.Validate("aaa")
+	if result.Approval || result.RuleType != DenyByDefault {
+		t.Fatal("deny expected", result.RuleType)
+	}
+
+	validator.
+		LongerThan(2).
+		Any(NewValidator().StartsWith("a")).
+		Any(NewValidator().StartsWith("b"))
+
+	for _, input := range []string{"aaa", "bbb"} {
+		if !validator.Validate(input).Approval || !validator.IsValid(input) {
+			t.Fatal("approval expected", input)
+		}
+	}
+
+	result = validator.Validate("ccc")
+	if result.Approval || result.RuleType != DenyByDefault {
+		t.Fatal("deny expected", result.RuleType)
+	}
+
+	if result.Reason != "\"allowed by an any rule\" is not met by \"ccc\"" {
+		t.Fatal("invalid reason", result.Reason)
+	}
+
+	result = validator.Validate("aa")
+	if result.Approval || result.RuleType != LongerThan {
+		t.Fatal("deny expected", result.RuleType)
+	}
+
+	results := validator.ValidateAll("c")
+	if len(results) != 2 || results[1].RuleType != DenyByDefault {
+		t.Fatal("two failures expected", len(results))
+	}
+}
+
+func TestOnlyScript(t *testing.T) {
+	validator := NewValidator().OnlyScript("Latin")
+
+	for _, input := range []string{"", "paypal", "Jean-Paul 2", "Árvíztűrő"} {
+		if !validator.Validate(input).Approval {
+			t.Fatal("approval expected", input)
+		}
+	}
+
+	result := validator.Validate("pаypal")
+	if result.Approval || result.RuleType != OnlyScript {
+		t.Fatal("deny expected", result.RuleType)
+	}
+
+	if result.Reason != "\"letters only from Latin script\" is not met by \"pаypal\" ('а' is Cyrillic)" {
+		t.Fatal("invalid reason", result.Reason)
+	}
+
+	result = NewValidator().RedactInput().OnlyScript("Latin").Validate("pаypal")
+	if result.Reason != "\"letters only from Latin script\" is not met" {
+		t.Fatal("invalid redacted reason", result.Reason)
+	}
+
+	if NewValidator().OnlyScript("Klingon").Err() == nil {
+		t.Fatal("error expected")
+	}
+}
+
+func TestNoControlCharacters(t *testing.T) {
+	validator := NewValidator().NoControlCharacters()
+
+	for _, input := range []string{"", "abc", "a b", "日本語"} {
+		if !validator.Validate(input).Approval {
+			t.Fatal("approval expected", input)
+		}
+	}
+
+	result := validator.Validate("a\x07b")
+	if result.Approval || result.RuleType != NoControlCharacters {
+		t.Fatal("deny expected", result.RuleType)
+	}
+
+	if result.Reason != "\"contains no control characters\" is not met by \"a\x07b\" (U+0007 is a control character)" {
+		t.Fatal("invalid reason", result.Reason)
+	}
+
+	if validator.Validate("a\tb").Approval {
+		t.Fatal("deny expected")
+	}
+}
+
+func TestPasswordStrength(t *testing.T) {
+	validator := NewValidator().PasswordStrength(PasswordOptions{
+		MinLength: 8,
+		Upper:     true,
+		Lower:     true,
+		Digit:     true,
+		Symbol:    true,
+	})
+
+	if !validator.Validate("Secr3t!pass").Approval {
+		t.Fatal("approval expected")
+	}
+
+	var tests = []struct {
+		input string
+		label string
+	}{
+		{input: "Se3t!", label: "password-length"},
+		{input: "secr3t!pass", label: "password-upper"},
+		{input: "SECR3T!PASS", label: "password-lower"},
+		{input: "Secret!pass", label: "password-digit"},
+		{input: "Secr3tpass", label: "password-symbol"},
+	}
+
+	for _, test := range tests {
+		result := validator.Validate(test.input)
+		if result.Approval {
+			t.Fatal("deny expected", test.input)
+		}
+
+		if result.RuleType != PasswordStrength {
+			t.Fatal("invalid rule type", result.RuleType)
+		}
+
+		if result.Label != test.label {
+			t.Fatal("invalid label", result.Label, test.label)
+		}
+	}
+
+	if NewValidator().PasswordStrength(PasswordOptions{Digit: true}).RuleCount() != 1 {
+		t.Fatal("only enabled requirements expected")
+	}
+}
+
+func TestPreprocess(t *testing.T) {
+	validator := NewValidator().
+		TrimSpace().
+		Preprocess(strings.ToLower).
+		StartsWith("abc").
+		IgnoreDuplicatesFor(time.Minute)
+	defer validator.StopIgnoringDuplicates()
+
+	if !validator.Validate("  ABCdef ").Approval {
+		t.Fatal("approval expected")
+	}
+
+	result := validator.Validate("abcdef")
+	if result.Approval || result.RuleType != IgnoreDuplicates {
+		t.Fatal("duplicate of preprocessed input expected", result.RuleType)
+	}
+
+	result = validator.Validate(" XYZ ")
+	if result.Reason != "\"starts with abc\" is not met by \"xyz\"" {
+		t.Fatal("invalid reason", result.Reason)
+	}
+
+	if !validator.IsValid(" ABCx ") {
+		t.Fatal("approval expected")
+	}
+}
+
+func TestNormalizeUnicode(t *testing.T) {
+	validator := NewValidator().NormalizeUnicode().EqualTo("caf\u00e9")
+
+	if !validator.Validate("cafe\u0301").Approval {
+		t.Fatal("approval expected")
+	}
+
+	if !validator.Validate("caf\u00e9").Approval {
+		t.Fatal("approval expected")
+	}
+}
+
+func TestIgnoreDuplicates(t *testing.T) {
+	validator := NewValidator().IgnoreDuplicatesFor(time.Millisecond)
+
+	result := validator.Validate("aaa")
+	if !result.Approval {
+		t.Fatal("approval expected")
+	}
+
+	result = validator.Validate("aaa")
+	if result.Approval {
+		t.Fatal("deny expected")
+	}
+
+	time.Sleep(2 * time.Millisecond)
+
+	result = validator.Validate("aaa")
+	if !result.Approval {
+		t.Fatal("approval expected")
+	}
+
+	result = validator.Validate("aaa")
+	if result.Approval {
+		t.Fatal("deny expected")
+	}
+
+	validator.StopIgnoringDuplicates()
+
+	result = validator.Validate("aaa")
+	if !result.Approval {
+		t.Fatal("approval expected")
+	}
+}
+
+type fakeClock struct {
+	mutex sync.Mutex
+	now   time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func TestIgnoreDuplicatesRetryAfter(t *testing.T) {
+	for _, failFast := range []bool{true, false} {
+		t.Run(fmt.Sprintf("FailFast=%t", failFast), func(t *testing.T) {
+			clock := &fakeClock{now: time.Unix(0, 0)}
+			validator := NewValidator(WithClock(clock.Now), WithDedup(time.Hour)).FailFast(failFast).NotEmpty()
+			defer validator.StopIgnoringDuplicates()
+
+			if result := validator.Validate("aaa"); !result.Approval || result.RetryAfter != 0 {
+				t.Fatal("approval without retry hint expected", result)
+			}
+
+			clock.Advance(20 * time.Minute)
+
+			if result := validator.Validate("aaa"); result.Approval || result.RetryAfter != 40*time.Minute {
+				t.Fatal("retry hint expected", result.RetryAfter)
+			}
+
+			if result := validator.Validate(""); result.RetryAfter != 0 {
+				t.Fatal("retry hint only expected for duplicates", result.RetryAfter)
+			}
+		})
+	}
+}
+
+func TestIgnoreDuplicatesExpiry(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	validator := NewValidator().WithClock(clock.Now).IgnoreDuplicatesFor(time.Hour)
+	defer validator.StopIgnoringDuplicates()
+
+	result := validator.Validate("aaa")
+	if !result.Approval {
+		t.Fatal("approval expected")
+	}
+
+	clock.Advance(time.Hour)
+
+	result = validator.Validate("aaa")
+	if result.Approval {
+		t.Fatal("deny expected")
+	}
+
+	clock.Advance(time.Nanosecond)
+
+	result = validator.Validate("aaa")
+	if !result.Approval {
+		t.Fatal("approval expected")
+	}
+
+	result = validator.Validate("aaa")
+	if result.Approval {
+		t.Fatal("deny expected")
+	}
+}
+
+func TestIgnoreDuplicatesNonPositive(t *testing.T) {
+	for _, duration := range []time.Duration{-time.Second, 0, 1} {
+		t.Run(duration.String(), func(t *testing.T) {
+			validator := NewValidator(WithDedup(time.Hour)).IgnoreDuplicatesFor(duration)
+			defer validator.StopIgnoringDuplicates()
+
+			validator.Validate("aaa")
+			if duration <= 0 && !validator.Validate("aaa").Approval {
+				t.Fatal("dedup should be disabled")
+			}
+		})
+	}
+
+	validator := NewValidator(WithDedup(0))
+	if !validator.Validate("aaa").Approval || !validator.Validate("aaa").Approval {
+		t.Fatal("dedup should be disabled")
+	}
+}
+
+func TestIgnoreDuplicatesWithLimit(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	validator := NewValidator().WithClock(clock.Now).IgnoreDuplicatesForWithLimit(time.Hour, 2)
+	defer validator.StopIgnoringDuplicates()
+
+	for _, input := range []string{"aaa", "bbb", "ccc"} {
+		clock.Advance(time.Second)
+		if !validator.Validate(input).Approval {
+			t.Fatal("approval expected", input)
+		}
+	}
+
+	if len(validator.recents) != 2 {
+		t.Fatal("invalid recents size", len(validator.recents))
+	}
+
+	result := validator.Validate("aaa")
+	if !result.Approval {
+		t.Fatal("approval of evicted entry expected")
+	}
+
+	result = validator.Validate("ccc")
+	if result.Approval {
+		t.Fatal("deny expected")
+	}
+}
+
+func TestIgnoreDuplicatesWithLimitEmptyInput(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	validator := NewValidator().WithClock(clock.Now).IgnoreDuplicatesForWithLimit(time.Hour, 2)
+	defer validator.StopIgnoringDuplicates()
+
+	for _, input := range []string{"", "bbb", "ccc"} {
+		clock.Advance(time.Second)
+		if !validator.Validate(input).Approval {
+			t.Fatal("approval expected", input)
+		}
+	}
+
+	if _, found := validator.recents[""]; found || len(validator.recents) != 2 {
+		t.Fatal("oldest entry should be evicted", validator.recents)
+	}
+
+	if validator.Validate("bbb").Approval || validator.Validate("ccc").Approval {
+		t.Fatal("deny of newer entries expected")
+	}
+}
+
+func TestOptions(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	validator := NewValidator(
+		WithClock(clock.Now),
+		WithMaxRecents(1),
+		WithDedup(time.Minute),
+	).StartsWith("a")
+	defer validator.StopIgnoringDuplicates()
+
+	if !validator.Validate("aaa").Approval {
+		t.Fatal("approval expected")
+	}
+
+	if validator.Validate("aaa").Approval {
+		t.Fatal("deny expected")
+	}
+
+	if !validator.Validate("abc").Approval {
+		t.Fatal("approval expected")
+	}
+
+	if !validator.Validate("aaa").Approval {
+		t.Fatal("approval of evicted entry expected")
+	}
+
+	clock.Advance(time.Minute + time.Nanosecond)
+
+	if !validator.Validate("aaa").Approval {
+		t.Fatal("approval of expired entry expected")
+	}
+}
+
+func TestIgnoreDuplicatesForTwice(t *testing.T) {
+	validator := NewValidator().IgnoreDuplicatesFor(time.Minute)
+	first := validator.close
+
+	validator.IgnoreDuplicatesFor(time.Minute)
+	second := validator.close
+
+	select {
+	case <-first:
+	default:
+		t.Fatal("first cleanup goroutine stop expected")
+	}
+
+	validator.StopIgnoringDuplicates()
+
+	select {
+	case <-second:
+	default:
+		t.Fatal("second cleanup goroutine stop expected")
+	}
+}
+
+func TestStopIgnoringDuplicatesWithoutStart(t *testing.T) {
+	validator := NewValidator()
+	validator.StopIgnoringDuplicates()
+	validator.StopIgnoringDuplicates()
+
+	result := validator.Validate("aaa")
+	if !result.Approval {
+		t.Fatal("approval expected")
+	}
+}
+
+func TestStopIgnoringDuplicatesTwice(t *testing.T) {
+	validator := NewValidator().IgnoreDuplicatesFor(time.Millisecond)
+	validator.StopIgnoringDuplicates()
+	validator.StopIgnoringDuplicates()
+
+	validator.IgnoreDuplicatesFor(time.Millisecond)
+
+	result := validator.Validate("aaa")
+	if !result.Approval {
+		t.Fatal("approval expected")
+	}
+
+	result = validator.Validate("aaa")
+	if result.Approval {
+		t.Fatal("deny expected")
+	}
+
+	validator.StopIgnoringDuplicates()
+}
+
+func TestFailFast(t *testing.T) {
+	validator := NewValidator().
+		ContainsACharacter().
+		ContainsANumber().
+		LongerThanOrEqual(5).
+		FailFast(false)
+
+	result := validator.Validate("abc")
+	if result.Approval {
+		t.Fatal("deny expected")
+	}
+
+	if result.RuleType != ContainsANumber {
+		t.Fatal("invalid rule type", result.RuleType, ContainsANumber)
+	}
+
+	expected := "\"contains a number\" is not met by \"abc\"; \"longer than or equal to 5\" is not met by \"abc\""
+	if result.Reason != expected {
+		t.Fatal("invalid reason", result.Reason, expected)
+	}
+
+	if len(result.Failures) != 2 || result.Failures[0] != ContainsANumber || result.Failures[1] != LongerThanOrEqual {
+		t.Fatal("invalid failures", result.Failures)
+	}
+
+	result = validator.Validate("abcde")
+	if len(result.Failures) != 1 || result.Failures[0] != ContainsANumber {
+		t.Fatal("invalid failures", result.Failures)
+	}
+
+	if !validator.Validate("abc12").Approval {
+		t.Fatal("approval expected")
+	}
+
+	result = validator.FailFast(true).Validate("abc")
+	if result.Reason != "\"contains a number\" is not met by \"abc\"" {
+		t.Fatal("invalid reason", result.Reason)
+	}
+
+	if result.Failures != nil {
+		t.Fatal("failures unexpected", result.Failures)
+	}
+}
+
+func TestLengthBetween(t *testing.T) {
+	validator := NewValidator().LengthBetween(2, 4)
+
+	for _, input := range []string{"aa", "aaa", "aaaa"} {
+		if !validator.Validate(input).Approval {
+			t.Fatal("approval expected", input)
+		}
+	}
+
+	result := validator.Validate("a")
+	if result.Approval || result.RuleType != MinLength {
+		t.Fatal("deny expected", result.RuleType)
+	}
+
+	result = validator.Validate("aaaaa")
+	if result.Approval || result.RuleType != MaxLength {
+		t.Fatal("deny expected", result.RuleType)
+	}
+}
+
+func TestIsValid(t *testing.T) {
+	validator := NewValidator().StartsWith("a").LongerThan(2).IgnoreDuplicatesFor(time.Minute)
+	defer validator.StopIgnoringDuplicates()
+
+	if !validator.IsValid("abc") {
+		t.Fatal("approval expected")
+	}
+
+	if validator.IsValid("abc") {
+		t.Fatal("deny expected")
+	}
+
+	if validator.IsValid("bcd") || validator.IsValid("ab") {
+		t.Fatal("deny expected")
+	}
+
+	validator.StopIgnoringDuplicates()
+
+	allocs := testing.AllocsPerRun(100, func() {
+		validator.IsValid("abc")
+	})
+	if allocs != 0 {
+		t.Fatal("no allocations expected", allocs)
+	}
+}
+
+func TestValidateValue(t *testing.T) {
+	if !ValidateValue(NewValidator().NumericRange(1, 10), 5).Approval {
+		t.Fatal("approve expected")
+	}
+
+	if ValidateValue(NewValidator().NumericRange(1, 10), 11).Approval {
+		t.Fatal("deny expected")
+	}
+
+	if !ValidateValue(NewValidator().EqualTo("green"), colorGreen).Approval {
+		t.Fatal("stringers should be validated by their string")
+	}
+
+	if !ValidateValue(NewValidator().IsDuration(), 90*time.Second).Approval {
+		t.Fatal("approve expected")
+	}
+
+	result := ValidateValue(NewValidator().LongerThan(4), 3.5)
+	if result.Approval || result.Reason != `"longer than 4" is not met by "3.5"` {
+		t.Fatal("invalid result", result)
+	}
+}
+
+func TestValidateBytes(t *testing.T) {
+	validator := NewValidator().
+		StartsWith("ab").
+		Labeled("prefix").
+		Contains("c").
+		LongerThan(3).
+		Regexp("^[a-z]+$").
+		Custom("custom", func(input string) bool {
+			return input != "abcx"
+		})
+
+	for _, input := range []string{"abcd", "abc", "xbcd", "abdd", "abc1", "abcx"} {
+		expected := validator.Validate(input)
+		result := validator.ValidateBytes([]byte(input))
+
+		if result.Approval != expected.Approval {
+			t.Fatal("invalid approval", input, result.Approval, expected.Approval)
+		}
+
+		if result.Reason != expected.Reason {
+			t.Fatal("invalid reason", result.Reason, expected.Reason)
+		}
+
+		if result.Label != expected.Label {
+			t.Fatal("invalid label", result.Label, expected.Label)
+		}
+	}
+
+	payload := []byte("ab" + strings.Repeat("c", 1<<16))
+	validator = NewValidator().StartsWith("ab").NotContains("d").EndsWith("c").LongerThanBytes(1 << 10)
+
+	allocs := testing.AllocsPerRun(10, func() {
+		validator.ValidateBytes(payload)
+	})
+	if allocs > 1 {
+		t.Fatal("input should not be converted", allocs)
+	}
+}
+
+func TestValidateBytesDuplicates(t *testing.T) {
+	validator := NewValidator(WithDedup(time.Hour)).TrimSpace().Contains("a")
+	defer validator.StopIgnoringDuplicates()
+
+	if !validator.ValidateBytes([]byte(" abc ")).Approval {
+		t.Fatal("approve expected")
+	}
+
+	if result := validator.ValidateBytes([]byte("abc")); result.Approval || result.RuleType != IgnoreDuplicates {
+		t.Fatal("duplicate deny expected", result)
+	}
+}
+
+func TestValidateAll(t *testing.T) {
+	validator := NewValidator().
+		ContainsACharacter().
+		ContainsANumber().
+		LongerThanOrEqual(5)
+
+	results := validator.ValidateAll("abc")
+	if len(results) != 2 {
+		t.Fatal("two failures expected", len(results))
+	}
+
+	if results[0].RuleType != ContainsANumber {
+		t.Fatal("invalid rule type", results[0].RuleType, ContainsANumber)
+	}
+
+	if results[1].RuleType != LongerThanOrEqual {
+		t.Fatal("invalid rule type", results[1].RuleType, LongerThanOrEqual)
+	}
+
+	for _, result := range results {
+		if result.Approval {
+			t.Fatal("deny expected")
+		}
+	}
+
+	results = validator.ValidateAll("abc123")
+	if len(results) != 0 {
+		t.Fatal("no failures expected", len(results))
+	}
+}
+
+func TestValidateAllDistinct(t *testing.T) {
+	validator := NewValidator().
+		NotEmpty().
+		ContainsANumber().
+		NotEmpty().
+		LongerThan(2).
+		NotEmpty().
+		WithMessage("required").
+		ContainsANumber()
+
+	results := validator.ValidateAll("")
+
+	expected := []RuleType{NotEmpty, ContainsANumber, LongerThan, NotEmpty}
+	if len(results) != len(expected) {
+		t.Fatal("invalid result count", len(results))
+	}
+
+	for i, ruleType := range expected {
+		if results[i].RuleType != ruleType {
+			t.Fatal("invalid rule type", i, results[i].RuleType, ruleType)
+		}
+	}
+
+	if results[3].Reason != "required" {
+		t.Fatal("different reasons should be kept", results[3].Reason)
+	}
+
+	result := validator.FailFast(false).Validate("")
+	if len(result.Failures) != len(expected) {
+		t.Fatal("aggregated failures should be distinct", result.Failures)
+	}
+}
+
+func TestDistinctKeepsSeverityAndLabel(t *testing.T) {
+	validator := NewValidator().NotEmpty().Warn().NotEmpty()
+	if validator.Validate("").Approval {
+		t.Fatal("deny expected")
+	}
+
+	if len(validator.ValidateAll("")) != 2 {
+		t.Fatal("warning and error should be kept", validator.ValidateAll(""))
+	}
+
+	required := func(input string) bool { return input != "" }
+	results := NewValidator().
+		Custom("required", required).Labeled("email").
+		Custom("required", required).Labeled("phone").
+		ValidateAll("")
+	if len(results) != 2 || results[0].Label != "email" || results[1].Label != "phone" {
+		t.Fatal("differently labeled results should be kept", results)
+	}
+}
+
+func TestValidateVerbose(t *testing.T) {
+	validator := NewValidator(WithDedup(time.Hour)).
+		StartsWith("a").
+		Labeled("prefix").
+		Custom("slow", func(input string) bool {
+			time.Sleep(10 * time.Millisecond)
+			return false
+		}).
+		EndsWith("c")
+	defer validator.StopIgnoringDuplicates()
+
+	outcomes := validator.ValidateVerbose("abc")
+	if len(outcomes) != 3 {
+		t.Fatal("invalid outcome count", len(outcomes))
+	}
+
+	if !outcomes[0].Passed || outcomes[0].RuleType != StartsWith || outcomes[0].Label != "prefix" {
+		t.Fatal("invalid outcome", outcomes[0])
+	}
+
+	if outcomes[1].Passed || outcomes[1].Reason != `"slow" is not met by "abc"` {
+		t.Fatal("invalid outcome", outcomes[1])
+	}
+
+	if outcomes[1].Duration < 10*time.Millisecond {
+		t.Fatal("invalid duration", outcomes[1].Duration)
+	}
+
+	if !outcomes[2].Passed {
+		t.Fatal("invalid outcome", outcomes[2])
+	}
+
+	validator.RemoveRule(Custom)
+
+	if !validator.Validate("abc").Approval {
+		t.Fatal("verbose validation should not track duplicates")
+	}
+}
+
+func TestErr(t *testing.T) {
+	validator := NewValidator().Regexp("t([a-z]+)t")
+	if validator.Err() != nil {
+		t.Fatal("no error expected", validator.Err())
+	}
+
+	validator = NewValidator().Regexp("[0-9]++").Regexp("[a-z]++")
+	if validator.Err() == nil {
+		t.Fatal("error expected")
+	}
+
+	if !strings.Contains(validator.Err().Error(), "[0-9]++") {
+		t.Fatal("first error expected", validator.Err())
+	}
+}
+
+func TestErrNested(t *testing.T) {
+	invalid := func(v *Validator) *Validator {
+		return v.Regexp("[")
+	}
+
+	var tests = []struct {
+		name      string
+		validator *Validator
+	}{
+		{name: "Not", validator: NewValidator().Not(invalid)},
+		{name: "Or", validator: NewValidator().Or(invalid)},
+		{name: "Any", validator: NewValidator().Any(invalid(NewValidator()))},
+		{name: "When", validator: NewValidator().When(func(string) bool { return true }, invalid)},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := test.validator.Err(); err == nil || !strings.Contains(err.Error(), "[") {
+				t.Fatal("inner error expected", err)
+			}
+		})
+	}
+}
+
+func TestMatchesOneOfInvalidPattern(t *testing.T) {
+	validator := NewValidator().MatchesOneOf([]string{"^a", "[0-9]++"})
+
+	if err := validator.Err(); err == nil || !strings.Contains(err.Error(), "[0-9]++") {
+		t.Fatal("compile error expected", err)
+	}
+
+	if !validator.Validate("abc").Approval {
+		t.Fatal("valid patterns should still match")
+	}
+}
+
+func TestUnknownCreditCardBrand(t *testing.T) {
+	validator := NewValidator().IsCreditCardBrand("unknown")
+	if validator.Err() == nil {
+		t.Fatal("error expected")
+	}
+
+	if validator.Validate("4111111111111111").Approval {
+		t.Fatal("deny expected")
+	}
+}
+
+func TestUnknownPhoneRegion(t *testing.T) {
+	validator := NewValidator().IsPhone("XX")
+	if validator.Err() == nil {
+		t.Fatal("error expected")
+	}
+
+	if validator.Validate("+14155552671").Approval {
+		t.Fatal("deny expected")
+	}
+}
+
+func TestDivisibleByZero(t *testing.T) {
+	for _, validator := range []*Validator{
+		NewValidator().LengthDivisibleBy(0),
+		NewValidator().ValueDivisibleBy(0),
+	} {
+		if validator.Err() == nil {
+			t.Fatal("error expected")
+		}
+
+		if validator.Validate("10").Approval {
+			t.Fatal("deny expected")
+		}
+	}
+}
+
+func TestSemVerPrecedence(t *testing.T) {
+	versions := []string{
+		"1.0.0-alpha",
+		"1.0.0-alpha.1",
+		"1.0.0-alpha.beta",
+		"1.0.0-beta",
+		"1.0.0-beta.2",
+		"1.0.0-beta.11",
+		"1.0.0-rc.1",
+		"1.0.0",
+	}
+
+	for i, version := range versions {
+		validator := NewValidator().SemVerAtLeast(version)
+		for j, input := range versions {
+			if validator.Validate(input).Approval != (j >= i) {
+				t.Fatal("invalid precedence", input, version)
+			}
+		}
+	}
+
+	if NewValidator().SemVerAtLeast("1.2").Err() == nil {
+		t.Fatal("error expected")
+	}
+}
+
+func TestSeverity(t *testing.T) {
+	validator := NewValidator(WithDedup(time.Hour)).
+		LongerThan(2).
+		Labeled("length").
+		ContainsANumber().
+		Warn().
+		IsLowercase().
+		Error()
+	defer validator.StopIgnoringDuplicates()
+
+	result := validator.Validate("abc")
+	if !result.Approval {
+		t.Fatal("warnings should not deny", result)
+	}
+
+	if !validator.IsValid("abd") {
+		t.Fatal("warnings should not deny in IsValid")
+	}
+
+	errs, warnings := PartitionBySeverity(validator.ValidateAll("AB"))
+	if len(errs) != 2 || len(warnings) != 1 {
+		t.Fatal("invalid partition", errs, warnings)
+	}
+
+	if warnings[0].RuleType != ContainsANumber || warnings[0].Severity != SeverityWarning {
+		t.Fatal("invalid warning", warnings[0])
+	}
+
+	if errs[0].Label != "length" || errs[0].Severity != SeverityError || errs[1].Severity != SeverityError {
+		t.Fatal("invalid errors", errs)
+	}
+
+	results := validator.ValidateAll("abe")
+	if len(results) != 1 || !results[0].IsWarning() {
+		t.Fatal("only a warning expected", results)
+	}
+
+	if result := validator.Validate("abe"); result.Approval || result.RuleType != IgnoreDuplicates {
+		t.Fatal("warnings should not prevent duplicate tracking", result)
+	}
+
+	if result := validator.Validate("A"); result.Severity != SeverityError {
+		t.Fatal("error severity expected", result)
+	}
+}
+
+func TestOrDenyByDefault(t *testing.T) {
+	validator := NewValidator().
+		Or(func(v *Validator) *Validator { return v.StartsWith("a") }).
+		DenyByDefault()
+
+	if result := validator.Validate("abc"); result.Approval || result.RuleType != DenyByDefault {
+		t.Fatal("or should not act as an allowlist", result)
+	}
+
+	validator.Any(NewValidator().EndsWith("c"))
+
+	if !validator.Validate("abc").Approval {
+		t.Fatal("approve expected")
+	}
+
+	if result := validator.Validate("bc"); result.Approval || result.RuleType != Or {
+		t.Fatal("or deny expected", result)
+	}
+}
+
+func TestDisableRule(t *testing.T) {
+	validator := NewValidator().
+		StartsWith("a").
+		Labeled("prefix").
+		LongerThan(3).
+		Any(NewValidator().EndsWith("c")).
+		DenyByDefault()
+
+	if validator.Validate("bcd").Approval {
+		t.Fatal("deny expected")
+	}
+
+	validator.DisableRuleByLabel("prefix").DisableRule(LongerThan)
+
+	if !validator.Validate("bc").Approval || !validator.IsValid("bc") {
+		t.Fatal("disabled rules should be skipped")
+	}
+
+	if validator.RuleCount() != 3 {
+		t.Fatal("disabled rules should be kept", validator.RuleCount())
+	}
+
+	if len(validator.ValidateVerbose("bc")) != 1 {
+		t.Fatal("disabled rules should not be reported")
+	}
+
+	validator.DisableRule(Any)
+
+	if result := validator.Validate("bc"); result.Approval || result.RuleType != DenyByDefault {
+		t.Fatal("disabled any rules should not allow", result)
+	}
+
+	validator.EnableRule(Any).EnableRuleByLabel("prefix")
+
+	if result := validator.Validate("bc"); result.Approval || result.RuleType != StartsWith {
+		t.Fatal("enabled rule deny expected", result)
+	}
+
+	if !validator.Validate("ac").Approval {
+		t.Fatal("approve expected")
+	}
+}
+
+func TestOnEvaluate(t *testing.T) {
+	type evaluation struct {
+		ruleType RuleType
+		input    string
+		passed   bool
+	}
+
+	evaluations := []evaluation{}
+	calls := 0
+	validator := NewValidator().
+		TrimSpace().
+		StartsWith("a").
+		EndsWith("c").
+		OnEvaluate(func(ruleType RuleType, input string, passed bool) {
+			evaluations = append(evaluations, evaluation{ruleType, input, passed})
+		}).
+		OnEvaluate(func(ruleType RuleType, input string, passed bool) {
+			calls++
+		})
+
+	validator.Validate(" abd ")
+
+	expected := []evaluation{
+		{StartsWith, "abd", true},
+		{EndsWith, "abd", false},
+	}
+
+	if len(evaluations) != len(expected) || calls != len(expected) {
+		t.Fatal("invalid evaluations", evaluations, calls)
+	}
+
+	for i := range expected {
+		if evaluations[i] != expected[i] {
+			t.Fatal("invalid evaluation", evaluations[i], expected[i])
+		}
+	}
+
+	evaluations = evaluations[:0]
+	validator.IsValid("xbc")
+
+	if len(evaluations) != 1 || evaluations[0] != (evaluation{StartsWith, "xbc", false}) {
+		t.Fatal("invalid evaluations", evaluations)
+	}
+}
+
+func TestCustomWithTimeout(t *testing.T) {
+	validator := NewValidator().CustomWithTimeout("lookup", 20*time.Millisecond, func(ctx context.Context, input string) bool {
+		if input == "slow" {
+			<-ctx.Done()
+			return true
+		}
+		return input == "ok"
+	})
+
+	if !validator.Validate("ok").Approval || !validator.IsValid("ok") {
+		t.Fatal("approve expected")
+	}
+
+	if result := validator.Validate("bad"); result.Approval || result.Reason != `"lookup" is not met by "bad"` {
+		t.Fatal("invalid deny", result)
+	}
+
+	result := validator.Validate("slow")
+	if result.Approval || result.RuleType != Custom || !strings.Contains(result.Reason, "timed out") {
+		t.Fatal("timeout deny expected", result)
+	}
+
+	if validator.IsValid("slow") {
+		t.Fatal("timeout deny expected in IsValid")
+	}
+
+	validator = NewValidator().CustomWithTimeout("lookup", time.Hour, func(ctx context.Context, input string) bool {
+		<-ctx.Done()
+		return true
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	result = validator.ValidateContext(ctx, "abc")
+	if result.Approval || !strings.Contains(result.Reason, context.DeadlineExceeded.Error()) {
+		t.Fatal("outer deadline deny expected", result)
+	}
+
+	if time.Since(start) > time.Second {
+		t.Fatal("outer deadline should apply")
+	}
+}
+
+func TestSubstring(t *testing.T) {
+	validator := NewValidator().
+		Regexp("^[A-Z]+$").
+		Substring(0, 2).
+		IsNumeric().
+		Substring(2, 6).
+		Labeled("serial").
+		LengthEqual(2).
+		Substring(6, 100)
+
+	var tests = []struct {
+		input    string
+		approved bool
+		ruleType RuleType
+		label    string
+	}{
+		{input: "HU1234ab", approved: true},
+		{input: "hu1234ab", ruleType: Regexp},
+		{input: "HU12x4ab", ruleType: IsNumeric, label: "serial"},
+		{input: "HU1234a", ruleType: LengthEqual},
+		{input: "HU1234abc", ruleType: LengthEqual},
+		{input: "HU", ruleType: IsNumeric, label: "serial"},
+		{input: "日本1234ab", ruleType: Regexp},
+	}
+
+	for _, test := range tests {
+		result := validator.Validate(test.input)
+		if result.Approval != test.approved || result.RuleType != test.ruleType || result.Label != test.label {
+			t.Fatal("invalid result", test.input, result)
+		}
+	}
+
+	result := validator.Validate("hu1234ab")
+	if result.Reason != `"regexp ^[A-Z]+$ in characters 0 to 2" is not met by "hu1234ab"` {
+		t.Fatal("invalid reason", result.Reason)
+	}
+
+	if !validator.ValidateBytes([]byte("HU1234ab")).Approval || validator.ValidateBytes([]byte("hu1234ab")).Approval {
+		t.Fatal("substring should apply to bytes")
+	}
+
+	if NewValidator().NotEmpty().Substring(3, 1).Err() == nil {
+		t.Fatal("error expected")
+	}
+}
+
+func TestFoldLanguage(t *testing.T) {
+	var tests = []struct {
+		name     string
+		turkish  *Validator
+		simple   *Validator
+		differs  string
+		approved []string
+		denied   []string
+	}{
+		{
+			name:     "ContainsFold",
+			turkish:  NewValidator(WithFoldLanguage(language.Turkish)).ContainsFold("ı"),
+			simple:   NewValidator().ContainsFold("ı"),
+			differs:  "KIRMIZI",
+			approved: []string{"KIRMIZI", "ışık"},
+			denied:   []string{"kirmizi", "İSTANBUL"},
+		},
+		{
+			name:     "IgnoreFold",
+			turkish:  NewValidator().FoldLanguage(language.Turkish).IgnoreFold("İstanbul"),
+			simple:   NewValidator().IgnoreFold("İstanbul"),
+			differs:  "istanbul",
+			approved: []string{"ıstanbul", "ISTANBUL"},
+			denied:   []string{"istanbul", "İSTANBUL"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			data, err := json.Marshal(test.turkish)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			loaded, err := FromJSON(data)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if loaded.language != language.Und {
+				t.Fatal("loading should not change the language of later rules", loaded.language)
+			}
+
+			for _, validator := range []*Validator{test.turkish, loaded} {
+				for _, input := range test.approved {
+					if !validator.Validate(input).Approval {
+						t.Fatal("approve expected", input)
+					}
+				}
+
+				for _, input := range test.denied {
+					if validator.Validate(input).Approval {
+						t.Fatal("deny expected", input)
+					}
+				}
+			}
+
+			if test.simple.Validate(test.differs).Approval == test.turkish.Validate(test.differs).Approval {
+				t.Fatal("simple folding should differ", test.differs)
+			}
+		})
+	}
+}
+
+func TestBlockList(t *testing.T) {
+	var tests = []struct {
+		name      string
+		validator *Validator
+		ruleType  RuleType
+		approved  []string
+		denied    map[string]string
+	}{
+		{
+			name:      "BlockList",
+			validator: NewValidator().BlockList([]string{"he", "she", "hers", "spam"}),
+			ruleType:  BlockList,
+			approved:  []string{"", "hi", "sh", "SPAM", "spa m"},
+			denied: map[string]string{
+				"ushers":  `"contains none of 4 blocked words" is not met by "ushers" (contains "she")`,
+				"spammer": `"contains none of 4 blocked words" is not met by "spammer" (contains "spam")`,
+				"hershey": `"contains none of 4 blocked words" is not met by "hershey" (contains "he")`,
+			},
+		},
+		{
+			name:      "NotContainsAny",
+			validator: NewValidator().NotContainsAny([]string{"foo", "bar"}),
+			ruleType:  NotContainsAny,
+			approved:  []string{"", "fo", "ba r", "FOO"},
+			denied: map[string]string{
+				"food":     `"does not contain any of [foo bar]" is not met by "food" (contains "foo")`,
+				"crowbar":  `"does not contain any of [foo bar]" is not met by "crowbar" (contains "bar")`,
+				"barefoot": `"does not contain any of [foo bar]" is not met by "barefoot" (contains "bar")`,
+			},
+		},
+		{
+			name:      "SubstringBlockList",
+			validator: NewValidator().BlockList([]string{"ab", "cd"}).Substring(2, 4),
+			ruleType:  BlockList,
+			approved:  []string{"abxx", "cdxy", "xabx"},
+			denied: map[string]string{
+				"xxcd": `"contains none of 2 blocked words in characters 2 to 4" is not met by "xxcd" (contains "cd")`,
+			},
+		},
+		{
+			name:      "BlockListFold",
+			validator: NewValidator().BlockListFold([]string{"Spam", "eggs"}),
+			ruleType:  BlockListFold,
+			approved:  []string{"", "ham", "egg"},
+			denied: map[string]string{
+				"SPAM":       `"contains none of 2 blocked words (case-insensitive)" is not met by "SPAM" (contains "Spam")`,
+				"Green EGGS": `"contains none of 2 blocked words (case-insensitive)" is not met by "Green EGGS" (contains "eggs")`,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			for _, input := range test.approved {
+				if !test.validator.Validate(input).Approval {
+					t.Fatal("approve expected", input)
+				}
+			}
+
+			for input, reason := range test.denied {
+				result := test.validator.Validate(input)
+				if result.Approval || result.RuleType != test.ruleType || result.Reason != reason {
+					t.Fatal("invalid deny", input, result)
+				}
+			}
+		})
+	}
+}
+
+func TestNotContainsAnyRedacted(t *testing.T) {
+	validator := NewValidator().NotContainsAny([]string{"secret"}).RedactInput()
+
+	result := validator.Validate("my secret token")
+	if result.Approval || result.Reason != `"does not contain any of [secret]" is not met` {
+		t.Fatal("matched term should be redacted", result.Reason)
+	}
+}
+
+func TestBlockListFromFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "blocked.txt")
+	if err := os.WriteFile(path, []byte("# blocked words\nspam\n\n  Scam  \n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	validator, err := NewValidator().BlockListFromFile(path, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !validator.Validate("hello # world").Approval {
+		t.Fatal("approve expected")
+	}
+
+	result := validator.Validate("a SCAM offer")
+	if result.Approval || result.RuleType != BlockListFold {
+		t.Fatal("deny expected", result)
+	}
+
+	if result.Reason != `"contains none of 2 blocked words (case-insensitive)" is not met by "a SCAM offer" (contains "Scam")` {
+		t.Fatal("invalid reason", result.Reason)
+	}
+
+	validator, err = NewValidator().BlockListFromFile(path, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !validator.Validate("a scam offer").Approval || validator.Validate("a Scam offer").Approval {
+		t.Fatal("case-sensitive matching expected")
+	}
+
+	if _, err := NewValidator().BlockListFromFile(filepath.Join(t.TempDir(), "missing"), false); err == nil {
+		t.Fatal("error expected")
+	}
+}
+
+func TestRequireAtLeastOneRule(t *testing.T) {
+	if !NewValidator().Validate("abc").Approval {
+		t.Fatal("validators without rules should approve by default")
+	}
+
+	validator := NewValidator().RequireAtLeastOneRule()
+
+	result := validator.Validate("abc")
+	if result.Approval || result.RuleType != RequireAtLeastOneRule || result.Reason != "validator has no rules" {
+		t.Fatal("deny expected", result)
+	}
+
+	if validator.IsValid("abc") || validator.ValidateBytes([]byte("abc")).Approval {
+		t.Fatal("deny expected")
+	}
+
+	validator.NotEmpty()
+
+	if !validator.Validate("abc").Approval || !validator.IsValid("abc") {
+		t.Fatal("approve expected")
+	}
+
+	validator.DisableRule(NotEmpty)
+
+	if validator.Validate("abc").Approval {
+		t.Fatal("disabled rules should not count")
+	}
+
+	if validator.Clone().Validate("abc").Approval {
+		t.Fatal("clone should keep the policy")
+	}
+}
+
+func TestRedactInput(t *testing.T) {
+	var tests = []struct {
+		name      string
+		validator *Validator
+		input     string
+		reason    string
+	}{
+		{
+			name:      "Rule",
+			validator: NewValidator().LongerThan(20),
+			input:     "secretpassword",
+			reason:    `"longer than 20" is not met`,
+		},
+		{
+			name:      "Empty",
+			validator: NewValidator().NotEmpty(),
+			input:     "",
+			reason:    `"must not be empty" is not met`,
+		},
+		{
+			name:      "Message",
+			validator: NewValidator().LongerThan(20).WithMessage("{input} is too short"),
+			input:     "secretpassword",
+			reason:    "[redacted] is too short",
+		},
+		{
+			name:      "Dynamic",
+			validator: NewValidator().NoControlCharacters(),
+			input:     "secret\n",
+			reason:    `"contains no control characters" is not met (U+000A is a control character)`,
+		},
+		{
+			name: "Custom",
+			validator: NewValidator().CustomWithReason(func(input string) (bool, string) {
+				return false, fmt.Sprintf("%q is taken", input)
+			}),
+			input:  "secret",
+			reason: `"[redacted]" is taken`,
+		},
+		{
+			name:      "DenyByDefault",
+			validator: NewValidator().Any(NewValidator().EqualTo("a")).DenyByDefault(),
+			input:     "secret",
+			reason:    `"allowed by an any rule" is not met`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result := test.validator.RedactInput().Validate(test.input)
+			if result.Approval || result.Reason != test.reason {
+				t.Fatal("invalid reason", result.Reason)
+			}
+
+			if outcomes := test.validator.ValidateAll(test.input); outcomes[0].Reason != test.reason {
+				t.Fatal("invalid reason", outcomes[0].Reason)
+			}
+
+			if test.validator.ValidateBytes([]byte(test.input)).Reason != test.reason {
+				t.Fatal("invalid bytes reason")
+			}
+		})
+	}
+}
+
+func TestStats(t *testing.T) {
+	validator := NewValidator(WithDedup(time.Hour)).StartsWith("a").LongerThan(2)
+	defer validator.StopIgnoringDuplicates()
+
+	validator.Validate("b")
+
+	if len(validator.Stats()) != 0 {
+		t.Fatal("stats should be opt-in", validator.Stats())
+	}
+
+	validator.TrackStats()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			validator.Validate("b")
+			validator.ValidateAll("x")
+		}()
+	}
+	wg.Wait()
+
+	validator.Validate("abc")
+	validator.Validate("abc")
+
+	expected := map[RuleType]int64{StartsWith: 100, LongerThan: 50, IgnoreDuplicates: 1}
+	stats := validator.Stats()
+	if len(stats) != len(expected) {
+		t.Fatal("invalid stats", stats)
+	}
+
+	for ruleType, count := range expected {
+		if stats[ruleType] != count {
+			t.Fatal("invalid count", ruleType, stats[ruleType], count)
+		}
+	}
+
+	validator.ResetStats()
+
+	if len(validator.Stats()) != 0 {
+		t.Fatal("stats should be reset", validator.Stats())
+	}
+
+	validator.Validate("b")
+
+	if validator.Stats()[StartsWith] != 1 {
+		t.Fatal("tracking should continue after reset", validator.Stats())
+	}
+}
+
+func TestStatsIgnoreWarnings(t *testing.T) {
+	validator := NewValidator().TrackStats().LongerThan(10).Warn()
+
+	if !validator.Validate("abc").Approval {
+		t.Fatal("approval expected")
+	}
+
+	if len(validator.Stats()) != 0 {
+		t.Fatal("warnings should not be counted", validator.Stats())
+	}
+}
+
+func TestNormalizeMAC(t *testing.T) {
+	validator := NewValidator().NormalizeMAC().IsMAC().EqualTo("00:00:5e:00:53:01")
+
+	for _, input := range []string{"00:00:5e:00:53:01", "00-00-5E-00-53-01", "00:00:5E:00:53:01"} {
+		if !validator.Validate(input).Approval {
+			t.Fatal("approve expected", input)
+		}
+	}
+
+	if result := validator.Validate("00-00-5E-00-53"); result.Approval || result.RuleType != IsMAC {
+		t.Fatal("invalid addresses should be left unchanged", result)
+	}
+}
+
+func TestReset(t *testing.T) {
+	validator := NewValidator().
+		StartsWith("a").
+		Regexp("[0-9]++").
+		IgnoreDuplicatesFor(time.Minute)
+
+	result := validator.Validate("bbb")
+	if result.Approval {
+		t.Fatal("deny expected")
+	}
+
+	validator.Reset()
+
+	if validator.Err() != nil {
+		t.Fatal("no error expected", validator.Err())
+	}
+
+	for i := 0; i < 2; i++ {
+		result = validator.Validate("bbb")
+		if !result.Approval {
+			t.Fatal("approval expected")
+		}
+	}
+
+	result = validator.EndsWith("a").Validate("bbb")
+	if result.Approval {
+		t.Fatal("deny expected")
+	}
+}
+
+func TestResetOptions(t *testing.T) {
+	evaluated := 0
+	validator := NewValidator(WithMaxRecents(1), WithFoldLanguage(language.Turkish)).
+		RequireAtLeastOneRule().
+		DenyByDefault().
+		RedactInput().
+		TrackStats().
+		FailFast(false).
+		OnEvaluate(func(RuleType, string, bool) { evaluated++ }).
+		Reset()
+
+	if !validator.Validate("x").Approval {
+		t.Fatal("approval expected")
+	}
+
+	result := validator.StartsWith("a").Validate("x")
+	if result.Approval || result.Reason != `"starts with a" is not met by "x"` {
+		t.Fatal("unredacted deny expected", result)
+	}
+
+	if evaluated != 0 || len(validator.Stats()) != 0 {
+		t.Fatal("no hooks or stats expected", evaluated, validator.Stats())
+	}
+
+	if validator.maxRecents != 0 || validator.language != (language.Tag{}) {
+		t.Fatal("fresh options expected", validator.maxRecents, validator.language)
+	}
+}
+
+func TestRuleIntrospection(t *testing.T) {
+	validator := NewValidator()
+	if validator.RuleCount() != 0 || len(validator.Rules()) != 0 {
+		t.Fatal("no rules expected")
+	}
+
+	validator.StartsWith("a").LongerThan(3).IgnoreAll([]string{"aaaa", "abcd"})
+
+	expected := []RuleType{StartsWith, LongerThan, Ignore, Ignore}
+	if validator.RuleCount() != len(expected) {
+		t.Fatal("invalid rule count", validator.RuleCount(), len(expected))
+	}
+
+	for i, ruleType := range validator.Rules() {
+		if ruleType != expected[i] {
+			t.Fatal("invalid rule type", ruleType, expected[i])
+		}
+	}
+}
+
+func TestValidateBatch(t *testing.T) {
+	validator := NewValidator().StartsWith("a")
+	inputs := []string{"aaa", "bbb", "abc", "cba", "a"}
+	expected := []bool{true, false, true, false, true}
+
+	for _, workers := range []int{0, 1, 4} {
+		results := validator.ValidateBatchParallel(inputs, workers)
+		if len(results) != len(inputs) {
+			t.Fatal("invalid result count", len(results), len(inputs))
+		}
+
+		for i, result := range results {
+			if result.Approval != expected[i] {
+				t.Fatal("invalid approval", inputs[i], result.Approval, expected[i])
+			}
+		}
+	}
+}
+
+func TestValidateMap(t *testing.T) {
+	validator := NewValidator().StartsWith("a").IgnoreDuplicatesFor(time.Minute)
+	defer validator.StopIgnoringDuplicates()
+
+	validator.Validate("abc")
+
+	results := validator.ValidateMap([]string{"aaa", "bbb", "aaa", "abc", "aaa"})
+	if len(results) != 3 {
+		t.Fatal("invalid result count", len(results))
+	}
+
+	if !results["aaa"].Approval {
+		t.Fatal("approval expected")
+	}
+
+	if results["bbb"].Approval || results["bbb"].RuleType != StartsWith {
+		t.Fatal("deny expected", results["bbb"].RuleType)
+	}
+
+	if results["abc"].Approval || results["abc"].RuleType != IgnoreDuplicates {
+		t.Fatal("deny expected", results["abc"].RuleType)
+	}
+}
+
+func TestValidateBatchDuplicates(t *testing.T) {
+	validator := NewValidator().IgnoreDuplicatesFor(time.Minute)
+	defer validator.StopIgnoringDuplicates()
+
+	results := validator.ValidateBatch([]string{"aaa", "aaa", "bbb", "aaa"})
+	expected := []bool{true, false, true, false}
+
+	for i, result := range results {
+		if result.Approval != expected[i] {
+			t.Fatal("invalid approval", i, result.Approval, expected[i])
+		}
+	}
+}
+
+func TestClone(t *testing.T) {
+	base := NewValidator().StartsWith("a").IgnoreDuplicatesFor(time.Minute)
+	defer base.StopIgnoringDuplicates()
+
+	result := base.Validate("aaa")
+	if !result.Approval {
+		t.Fatal("approval expected")
+	}
+
+	clone := base.Clone().EndsWith("z")
+
+	if base.RuleCount() != 1 || clone.RuleCount() != 2 {
+		t.Fatal("independent rules expected", base.RuleCount(), clone.RuleCount())
+	}
+
+	result = clone.Validate("aaz")
+	if !result.Approval {
+		t.Fatal("approval expected")
+	}
+
+	result = clone.Validate("aaz")
+	if !result.Approval {
+		t.Fatal("approval expected")
+	}
+
+	result = clone.Validate("aaa")
+	if result.Approval || result.RuleType != EndsWith {
+		t.Fatal("deny expected", result.RuleType)
+	}
+
+	result = base.Validate("aaa")
+	if result.Approval || result.RuleType != IgnoreDuplicates {
+		t.Fatal("deny expected", result.RuleType)
+	}
+
+	limited := NewValidator(WithMaxRecents(1)).Clone().IgnoreDuplicatesFor(time.Hour)
+	defer limited.StopIgnoringDuplicates()
+
+	limited.Validate("aaa")
+	limited.Validate("bbb")
+
+	if len(limited.recents) != 1 {
+		t.Fatal("clone should keep the recents limit", len(limited.recents))
+	}
+
+	base.TrackStats().Validate("b")
+
+	clone = base.Clone()
+	if len(clone.Stats()) != 0 {
+		t.Fatal("clone should start with fresh stats", clone.Stats())
+	}
+
+	clone.Validate("b")
+	clone.Validate("c")
+
+	if clone.Stats()[StartsWith] != 2 || base.Stats()[StartsWith] != 1 {
+		t.Fatal("independent stats expected", clone.Stats(), base.Stats())
+	}
+}
+
+func TestConcurrentBuildAndValidate(t *testing.T) {
+	validator := NewValidator().IgnoreDuplicatesFor(time.Millisecond)
+	defer validator.StopIgnoringDuplicates()
+
+	wg := sync.WaitGroup{}
+	wg.Add(2)
+
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			validator.ContainsANumber().Regexp("[0-9]++")
+		}
+	}()
+
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			validator.Validate(fmt.Sprint(i))
+			validator.ValidateAll(fmt.Sprint(i))
+			validator.Rules()
+		}
+	}()
+
+	wg.Wait()
+
+	if validator.RuleCount() != 200 {
+		t.Fatal("invalid rule count", validator.RuleCount())
+	}
+}
+
+func TestConcurrentDuplicates(t *testing.T) {
+	validator := NewValidator(WithDedup(time.Hour)).NotEmpty()
+	defer validator.StopIgnoringDuplicates()
+
+	var approvals int64
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if validator.Validate("abc").Approval {
+				atomic.AddInt64(&approvals, 1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if approvals != 1 {
+		t.Fatal("exactly one approval expected", approvals)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	base := NewValidator().StartsWith("a").IgnoreDuplicatesFor(time.Minute)
+	defer base.StopIgnoringDuplicates()
+
+	fragment := NewValidator().EndsWith("z").Regexp("[0-9]++").IgnoreDuplicatesFor(time.Minute)
+	defer fragment.StopIgnoringDuplicates()
+
+	base.Merge(fragment)
+
+	expected := []RuleType{StartsWith, EndsWith, Regexp}
+	for i, ruleType := range base.Rules() {
+		if ruleType != expected[i] {
+			t.Fatal("invalid rule type", ruleType, expected[i])
+		}
+	}
+
+	if fragment.RuleCount() != 2 {
+		t.Fatal("fragment unchanged expected", fragment.RuleCount())
+	}
+
+	if base.Err() == nil {
+		t.Fatal("error expected")
+	}
+}
+
+func TestString(t *testing.T) {
+	validator := NewValidator()
+	if fmt.Sprintf("%s", validator) != "[]" {
+		t.Fatal("invalid string", validator.String())
+	}
+
+	validator.StartsWith("123").LongerThan(4).Regexp("^[0-9]+$")
+
+	expected := "[starts with 123 AND longer than 4 AND regexp ^[0-9]+$]"
+	if fmt.Sprintf("%s", validator) != expected {
+		t.Fatal("invalid string", validator.String(), expected)
+	}
+}
+
 func TestMultiple(t *testing.T) {
 	validator := NewValidator().
 		ContainsACharacter().